@@ -0,0 +1,133 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+func runAnnotate(args []string) {
+	fs := flag.NewFlagSet("annotate", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	var add, remove stringList
+	fs.Var(&add, "flag", "Add this flag to the trade, e.g. mistake (repeatable)")
+	fs.Var(&remove, "remove", "Remove this flag from the trade (repeatable)")
+	clearNote := fs.Bool("clear", false, "Remove all of the trade's flags")
+	list := fs.Bool("list", false, "List every annotated trade and its flags")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue annotate [options] <trade-id>\n       tvue annotate --list\n\nFlags a trade locally (e.g. mistake, a+ setup) in %s in the data\ndirectory. Flags are never sent to Tradervue; filter and break down by them\nwith --with-annotations on summary and tags. With no options, prints the\ntrade's flags.\n\nOptions:\n", summary.AnnotationsFile)
+		fs.PrintDefaults()
+	}
+
+	// Flags may come before or after the trade ID, so parse past each word
+	var words []string
+	for rest := args; ; {
+		if err := fs.Parse(rest); err != nil {
+			os.Exit(1)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		words = append(words, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+
+	ann, err := summary.LoadAnnotations(dirs.path())
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	if *list {
+		if len(words) > 0 || len(add) > 0 || len(remove) > 0 || *clearNote {
+			log.Fatalf("Error: --list takes no trade ID or other options")
+		}
+		printAnnotations(ann)
+		return
+	}
+
+	if len(words) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	id, err := strconv.Atoi(words[0])
+	if err != nil {
+		log.Fatalf("Error: invalid trade ID %q", words[0])
+	}
+	if *clearNote && len(add) > 0 {
+		log.Fatalf("Error: --clear conflicts with --flag; use one or the other")
+	}
+
+	if len(add) == 0 && len(remove) == 0 && !*clearNote {
+		if flags := ann.Flags(id); len(flags) > 0 {
+			fmt.Println(strings.Join(flags, ", "))
+		} else {
+			log.Printf("Trade %d has no flags.", id)
+		}
+		return
+	}
+
+	if len(add) > 0 && !tradeExported(dirs.path(), id) {
+		log.Printf("Warning: trade %d is not in the exported data; flagging it anyway", id)
+	}
+	if *clearNote {
+		remove = append(remove, ann.Flags(id)...)
+	}
+	flags := ann.Flag(id, add, remove)
+	if err := ann.Save(dirs.path()); err != nil {
+		log.Fatalf("Error saving annotations: %v", err)
+	}
+
+	if len(flags) == 0 {
+		log.Printf("Trade %d has no flags.", id)
+		return
+	}
+	log.Printf("Trade %d: %s", id, strings.Join(flags, ", "))
+}
+
+// tradeExported reports whether a trade with the given ID is in the day
+// files of dataDir.
+func tradeExported(dataDir string, id int) bool {
+	gen := summary.NewGenerator(dataDir)
+	rows, err := gen.Trades(summary.Options{})
+	if err != nil {
+		return false
+	}
+	for _, r := range rows {
+		if r.Trade.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// printAnnotations lists the annotated trades by ID with their flags.
+func printAnnotations(ann summary.Annotations) {
+	if len(ann) == 0 {
+		log.Println("No trades are annotated yet.")
+		return
+	}
+
+	ids := make([]int, 0, len(ann))
+	for id := range ann {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TRADE\tFLAGS\tUPDATED")
+	fmt.Fprintln(tw, "─────\t─────\t───────")
+	for _, id := range ids {
+		a := ann[id]
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", id, strings.Join(a.Flags, ", "), a.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	tw.Flush()
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jefrnc/tradervue-utils/pkg/exporter"
+)
+
+func runBundle(args []string) {
+	fs := flag.NewFlagSet("bundle", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	fromDate := fs.String("from", "", "Start date filter (yyyy-mm-dd)")
+	toDate := fs.String("to", "", "End date filter (yyyy-mm-dd)")
+	outputFile := fs.String("output", "", "Output file, gzipped if it ends in .gz (default: stdout)")
+
+	// Short aliases
+	fs.StringVar(outputFile, "o", "", "")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue bundle [options]\n\nWrites the exported day files as one JSON array of day exports.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	var w io.Writer = os.Stdout
+	if *outputFile != "" {
+		f, err := os.Create(*outputFile)
+		if err != nil {
+			log.Fatalf("Error creating output file: %v", err)
+		}
+		defer f.Close()
+		w = f
+
+		if strings.HasSuffix(*outputFile, ".gz") {
+			zw := gzip.NewWriter(f)
+			defer zw.Close()
+			w = zw
+		}
+	}
+
+	n, err := exporter.Bundle(w, dirs.path(), *fromDate, *toDate)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if n == 0 {
+		log.Println("No exported data found. Run 'tvue export' first.")
+		return
+	}
+	if *outputFile != "" {
+		log.Printf("Bundled %d days into %s", n, *outputFile)
+	}
+}
+
+func runUnbundle(args []string) {
+	fs := flag.NewFlagSet("unbundle", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	compress := fs.Bool("gzip", false, "Write day files gzipped (.json.gz)")
+	force := fs.Bool("force", false, "Replace day files that already exist (default: skip them)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue unbundle [options] <file>\n\nSplits a file written by 'tvue bundle' back into day files. Use - to read\nstdin; a file ending in .gz is decompressed.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	input := fs.Arg(0)
+
+	var r io.Reader = os.Stdin
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		defer f.Close()
+		r = f
+
+		if strings.HasSuffix(input, ".gz") {
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				log.Fatalf("Error: decompressing %s: %v", input, err)
+			}
+			r = zr
+		}
+	}
+
+	exp := exporter.New(nil, dirs.path())
+	result, err := exp.Unbundle(r, exporter.UnbundleOptions{Compress: *compress, Overwrite: *force})
+	if result != nil && result.Written > 0 {
+		log.Printf("Wrote %d day files to %s", result.Written, dirs.path())
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if len(result.Skipped) > 0 {
+		log.Printf("Skipped %d days that already have a day file (use --force to replace them): %s",
+			len(result.Skipped), strings.Join(result.Skipped, ", "))
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jefrnc/tradervue-utils/pkg/exporter"
+)
+
+func runCheck(args []string) {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue check [options]\n\nChecks day files against the checksums in manifest.json.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	report, err := exporter.CheckManifest(dirs.path())
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	for _, p := range report.Problems {
+		fmt.Printf("FAIL  %s: %s\n", p.Date, p.Problem)
+	}
+	if len(report.Untracked) > 0 {
+		fmt.Printf("Note: %d day files are not in the manifest (re-export them with --force to start tracking)\n", len(report.Untracked))
+	}
+
+	if len(report.Problems) > 0 {
+		fmt.Printf("%d of %d day files failed verification. Re-export them with: tvue export --from <date> --to <date> --force\n",
+			len(report.Problems), report.Checked)
+		os.Exit(1)
+	}
+
+	fmt.Printf("OK: %d day files match the manifest\n", report.Checked)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/stats"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+func runCompare(args []string) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	rangeA := fs.String("a", "", "Baseline range, FROM:TO (e.g. 2025-01-01:2025-01-31)")
+	rangeB := fs.String("b", "", "Range to compare with the baseline, FROM:TO")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	scratch := fs.Float64("scratch-threshold", 0, "Count trades with |P&L| up to this amount as scratches")
+	currency := fs.String("currency", "", "Only include trades made in this currency (e.g. USD)")
+	var symbols stringList
+	fs.Var(&symbols, "symbol", "Only include this symbol (repeatable)")
+	aliases := addAliasFlag(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue compare --a FROM:TO --b FROM:TO [options]\n\nShows two date ranges side by side: net P&L, win rate, trade count, and\naverage daily P&L, with the change from A to B and per-symbol changes.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	if *rangeA == "" || *rangeB == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	a, err := stats.ParseDateRange(*rangeA)
+	if err != nil {
+		log.Fatalf("Error: --a: %v", err)
+	}
+	b, err := stats.ParseDateRange(*rangeB)
+	if err != nil {
+		log.Fatalf("Error: --b: %v", err)
+	}
+	if *scratch < 0 {
+		log.Fatalf("Error: --scratch-threshold must not be negative")
+	}
+	aliasMap, err := aliases.aliases()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	gen := summary.NewGenerator(dirs.path())
+	generate := func(r stats.DateRange) []models.DailySummary {
+		summaries, err := gen.Generate(summary.Options{
+			FromDate: r.From,
+			ToDate:   r.To,
+			Symbols:  symbols,
+
+			ScratchThreshold: *scratch,
+			Currency:         *currency,
+			Aliases:          aliasMap,
+		})
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return summaries
+	}
+	summariesA, summariesB := generate(a), generate(b)
+
+	if len(summariesA) == 0 && len(summariesB) == 0 {
+		log.Println("No exported data in either range. Run 'tvue export' first.")
+		return
+	}
+
+	c := stats.Compare(a, b, summariesA, summariesB)
+	if *jsonOutput {
+		writeJSON(c)
+		return
+	}
+	stats.PrintComparison(os.Stdout, c)
+}
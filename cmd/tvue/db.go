@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jefrnc/tradervue-utils/internal/db"
+)
+
+func runDB(args []string) {
+	if len(args) < 1 || args[0] != "import" {
+		fmt.Fprintf(os.Stderr, "Usage: tvue db import [options]\n")
+		os.Exit(1)
+	}
+
+	fs := flag.NewFlagSet("db import", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	dbPath := fs.String("db", "trades.db", "SQLite database file")
+	fromDate := fs.String("from", "", "Start date filter (yyyy-mm-dd)")
+	toDate := fs.String("to", "", "End date filter (yyyy-mm-dd)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue db import [options]\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		os.Exit(1)
+	}
+
+	store, err := db.Open(*dbPath)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	defer store.Close()
+
+	stats, err := store.ImportDir(dirs.path(), *fromDate, *toDate)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	log.Printf("Imported %d days into %s: %d trades, %d executions, %d journal entries",
+		stats.Days, *dbPath, stats.Trades, stats.Executions, stats.Journal)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/jefrnc/tradervue-utils/internal/config"
+	"github.com/jefrnc/tradervue-utils/pkg/api"
+	"github.com/jefrnc/tradervue-utils/pkg/exporter"
+)
+
+// checklist prints doctor results and remembers whether anything failed.
+type checklist struct {
+	failed bool
+}
+
+func (c *checklist) pass(name, detail string) {
+	fmt.Printf("[PASS] %s: %s\n", name, detail)
+}
+
+func (c *checklist) fail(name, detail, hint string) {
+	c.failed = true
+	fmt.Printf("[FAIL] %s: %s\n", name, detail)
+	if hint != "" {
+		fmt.Printf("       hint: %s\n", hint)
+	}
+}
+
+func (c *checklist) skip(name, reason string) {
+	fmt.Printf("[SKIP] %s: %s\n", name, reason)
+}
+
+func runDoctor(args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+
+	creds := addCredentialFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue doctor [options]\n\nChecks credentials, API access, and the data directory.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	var c checklist
+
+	cfg, err := creds.load()
+	if err != nil {
+		c.fail("Credentials", err.Error(), "copy .env.example to .env and fill it in, or pass --token")
+		c.skip("API access", "no credentials")
+		// Still check the data dir the flags point at
+		cfg = &config.Config{DataDir: config.DataDir(*creds.dataDir, *creds.profile)}
+	} else {
+		if cfg.UsesToken() {
+			c.pass("Credentials", "API token configured")
+		} else {
+			c.pass("Credentials", fmt.Sprintf("username %q and password configured", cfg.Username))
+		}
+		checkAPI(&c, cfg)
+	}
+
+	checkDataDir(&c, cfg.DataDir)
+
+	if c.failed {
+		os.Exit(1)
+	}
+	fmt.Println("All checks passed.")
+}
+
+// checkAPI makes one small request to confirm the API accepts the credentials.
+func checkAPI(c *checklist, cfg *config.Config) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, max(api.DefaultTimeout, cfg.Timeout))
+	defer cancel()
+
+	// One attempt is enough to diagnose; retries would only delay the answer
+	cfg.MaxRetries = 1
+	if err := newClient(cfg).Ping(ctx); err != nil {
+		hint := "check your network connection; rerun with --debug to see the request"
+		if strings.Contains(err.Error(), "HTTP 401") {
+			hint = "Tradervue rejected the credentials; check the username/password or regenerate the API token"
+		} else if errors.Is(err, api.ErrForbidden) {
+			hint = "the credentials work, but Tradervue refused API access; check that your plan includes the API"
+		}
+		c.fail("API access", err.Error(), hint)
+		return
+	}
+	c.pass("API access", "GET /trades?count=1 succeeded")
+}
+
+// checkDataDir checks the data directory, state file, and day files.
+func checkDataDir(c *checklist, dataDir string) {
+	info, err := os.Stat(dataDir)
+	switch {
+	case os.IsNotExist(err):
+		c.fail("Data directory", dataDir+" does not exist", "run 'tvue export' to create it, or point --data-dir at your data")
+		c.skip("State file", "no data directory")
+		c.skip("Day files", "no data directory")
+		return
+	case err != nil:
+		c.fail("Data directory", err.Error(), "")
+		return
+	case !info.IsDir():
+		c.fail("Data directory", dataDir+" is not a directory", "point --data-dir or TVUE_DATA_DIR at a directory")
+		return
+	}
+
+	probe, err := os.CreateTemp(dataDir, ".doctor-*")
+	if err != nil {
+		c.fail("Data directory", dataDir+" is not writable: "+err.Error(), "fix the directory permissions")
+	} else {
+		probe.Close()
+		os.Remove(probe.Name())
+		c.pass("Data directory", dataDir+" exists and is writable")
+	}
+
+	state, err := exporter.LoadState(dataDir)
+	switch {
+	case err != nil:
+		c.fail("State file", "state.json does not parse: "+err.Error(),
+			"delete "+filepath.Join(dataDir, "state.json")+"; the next export rebuilds it")
+	case state == nil:
+		c.pass("State file", "not created yet (nothing exported)")
+	default:
+		detail := fmt.Sprintf("last export %s, first trade %s", state.LastExportDate, state.FirstTradeDate)
+		if state.Timezone != "" {
+			detail += ", days grouped by " + state.Timezone
+		}
+		c.pass("State file", detail)
+	}
+
+	checked, problems, err := exporter.CheckDayFiles(dataDir)
+	if err != nil {
+		c.fail("Day files", err.Error(), "")
+		return
+	}
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Printf("       %s: %s\n", p.Date, p.Problem)
+		}
+		c.fail("Day files", fmt.Sprintf("%d of %d day files have problems", len(problems), checked),
+			"re-export them with: tvue export --from <date> --to <date> --force")
+		return
+	}
+	c.pass("Day files", fmt.Sprintf("%d day files parse and match their dates", checked))
+}
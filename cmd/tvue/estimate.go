@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/exporter"
+)
+
+func runEstimate(args []string) {
+	fs := flag.NewFlagSet("estimate", flag.ExitOnError)
+
+	creds := addCredentialFlags(fs)
+	fromDate := fs.String("from", "", "Start date (yyyy-mm-dd)")
+	toDate := fs.String("to", "", "End date (yyyy-mm-dd)")
+	withExecs := fs.Bool("with-executions", false, "Include execution requests in the estimate")
+	force := fs.Bool("force", false, "Estimate a full re-export instead of an incremental one")
+	quiet := fs.Bool("quiet", false, "Log only the final summary, warnings, and errors")
+	verbose := fs.Bool("verbose", false, "Also log every page fetched")
+
+	// Short aliases
+	fs.BoolVar(quiet, "q", false, "")
+	fs.BoolVar(verbose, "v", false, "")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue estimate [options]\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	if *quiet && *verbose {
+		log.Fatalf("Error: --quiet conflicts with --verbose; use one or the other")
+	}
+
+	cfg, err := creds.load()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	client := newClient(cfg)
+	exp := exporter.New(client, cfg.DataDir)
+	exp.SetLogger(newLogger(cfg))
+
+	opts := exporter.Options{
+		WithExecutions: *withExecs,
+		FromDate:       *fromDate,
+		ToDate:         *toDate,
+		Force:          *force,
+		Quiet:          *quiet,
+		Verbose:        *verbose,
+		Timezone:       cfg.Timezone,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	est, err := exp.Estimate(ctx, opts)
+	if err != nil {
+		log.Fatalf("Estimate failed: %v", err)
+	}
+
+	if est.Trades == 0 {
+		fmt.Printf("No trades to export between %s and %s.\n", est.StartDate, est.EndDate)
+		return
+	}
+
+	fmt.Printf("Export estimate for %s to %s:\n", est.StartDate, est.EndDate)
+	fmt.Printf("  Trading days:    %d\n", est.Days)
+	fmt.Printf("  Trades:          %d\n", est.Trades)
+	fmt.Printf("  Executions:      %d\n", est.Executions)
+	fmt.Printf("  API requests:    %d (%d trade pages + %d execution lookups)\n",
+		est.TotalRequests(), est.PageRequests, est.ExecutionRequests)
+	fmt.Printf("  Estimated time:  ~%s\n", est.Duration.Round(time.Second))
+	fmt.Printf("  Estimated size:  ~%s\n", formatBytes(est.Bytes))
+	if !*withExecs && est.Executions > 0 {
+		fmt.Printf("\nWith --with-executions this would add %d requests.\n", est.Trades)
+	}
+}
+
+// formatBytes renders a byte count in human-readable units.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/config"
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+// credentialFlags holds the credential, data-dir, timezone, CA, and logging flags
+// shared by every command that talks to the Tradervue API.
+type credentialFlags struct {
+	username *string
+	password *string
+	token    *string
+	dataDir  *string
+	profile  *string
+	timezone *string
+	caCert   *string
+	debug    *bool
+	logJSON  *bool
+
+	logFile    *string
+	logMaxSize *int
+
+	userAgent *string
+	contact   *string
+
+	passwordStdin *bool
+	passwordFile  *string
+	strict        *bool
+}
+
+// addCredentialFlags registers the shared API flags (and short aliases) on fs.
+func addCredentialFlags(fs *flag.FlagSet) *credentialFlags {
+	f := &credentialFlags{
+		username: fs.String("username", "", "Tradervue username"),
+		password: fs.String("password", "", "Tradervue password"),
+		token:    fs.String("token", "", "Tradervue API token (used instead of username/password)"),
+		dataDir:  fs.String("data-dir", "", "Data directory (default: ./data)"),
+		profile:  fs.String("profile", "", "Named account profile (reads TRADERVUE_*_<PROFILE>, data in ./data/<profile>)"),
+		timezone: fs.String("timezone", "", "Timezone for grouping trades into days (default: America/New_York)"),
+		caCert:   fs.String("ca-cert", "", "PEM file of extra root CAs to trust (for proxies that intercept HTTPS)"),
+		debug:    fs.Bool("debug", false, "Log every API request and response (credentials redacted)"),
+		logJSON:  fs.Bool("log-json", false, "Write log messages to stderr as JSON lines"),
+
+		logFile:    fs.String("log-file", "", "Also append log messages to this file"),
+		logMaxSize: fs.Int("log-max-size", 0, "Rotate --log-file once it reaches this many MB, keeping 3 old files (0 = never)"),
+
+		userAgent: fs.String("user-agent", "", "User-Agent sent to Tradervue (default: "+config.DefaultUserAgent+")"),
+		contact:   fs.String("contact", "", "Email address added to the User-Agent, so Tradervue can reach you about your client"),
+
+		passwordStdin: fs.Bool("password-stdin", false, "Read the Tradervue password from standard input"),
+		passwordFile:  fs.String("password-file", "", "Read the Tradervue password from this file (must be mode 0600)"),
+		strict:        fs.Bool("strict", false, "Refuse a password given on the command line"),
+	}
+
+	// Short aliases
+	fs.StringVar(f.username, "u", "", "")
+	fs.StringVar(f.password, "p", "", "")
+	fs.StringVar(f.dataDir, "d", "", "")
+
+	return f
+}
+
+// load resolves the configuration, letting flag values override env vars.
+// A password given with --password shows up in shell history and ps, so it
+// draws a warning, or an error with --strict.
+func (f *credentialFlags) load() (*config.Config, error) {
+	if *f.logFile != "" {
+		f.openLogFile()
+	}
+	if *f.logJSON {
+		// Also routes the log package, so the CLI's own messages match
+		slog.SetDefault(slog.New(slog.NewJSONHandler(logOutput, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	}
+
+	if *f.password != "" {
+		if *f.strict {
+			return nil, fmt.Errorf("--strict: refusing a password on the command line; use --password-stdin, --password-file, or TRADERVUE_PASSWORD in .env")
+		}
+		log.Printf("Warning: a password given with --password is visible in shell history and ps; prefer --password-stdin, --password-file, or TRADERVUE_PASSWORD in .env")
+	}
+
+	return config.Load(config.Flags{
+		Username: *f.username,
+		Password: *f.password,
+		Token:    *f.token,
+		DataDir:  *f.dataDir,
+		Profile:  *f.profile,
+		Timezone: *f.timezone,
+		CACert:   *f.caCert,
+		Debug:    *f.debug,
+		LogJSON:  *f.logJSON,
+
+		UserAgent: *f.userAgent,
+		Contact:   *f.contact,
+
+		PasswordStdin: *f.passwordStdin,
+		PasswordFile:  *f.passwordFile,
+	})
+}
+
+// openLogFile tees log output to --log-file from here on. A file that can't
+// be opened is only a warning: the run goes ahead logging to stderr alone.
+func (f *credentialFlags) openLogFile() {
+	if *f.logMaxSize < 0 {
+		log.Printf("Warning: ignoring negative --log-max-size %d", *f.logMaxSize)
+		*f.logMaxSize = 0
+	}
+	path := config.ExpandPath(*f.logFile)
+	file, err := logging.OpenRotating(path, int64(*f.logMaxSize)<<20)
+	if err != nil {
+		log.Printf("Warning: can't open log file, logging to stderr only: %v", err)
+		return
+	}
+
+	logOutput = io.MultiWriter(os.Stderr, file)
+	log.SetOutput(logOutput)
+	if !*f.logJSON {
+		// Plain lines carry no time, so mark where each run starts
+		fmt.Fprintf(file, "--- %s tvue %s ---\n", time.Now().Format(time.RFC3339), os.Args[1])
+	}
+}
+
+// dataDirFlags holds the data-dir flags of commands that only read exported
+// data and need no credentials.
+type dataDirFlags struct {
+	dataDir *string
+	profile *string
+}
+
+// addDataDirFlags registers --data-dir (with -d) and --profile on fs.
+func addDataDirFlags(fs *flag.FlagSet) *dataDirFlags {
+	f := &dataDirFlags{
+		dataDir: fs.String("data-dir", "", "Data directory (default: ./data)"),
+		profile: fs.String("profile", "", "Named account profile (data in ./data/<profile>)"),
+	}
+
+	// Short aliases
+	fs.StringVar(f.dataDir, "d", "", "")
+
+	return f
+}
+
+// path returns the data directory, resolved as for commands that load
+// credentials; an explicit --data-dir always wins.
+func (f *dataDirFlags) path() string {
+	return config.DataDir(*f.dataDir, *f.profile)
+}
+
+// aliasFlags holds the repeatable --alias flag of commands that aggregate
+// by symbol.
+type aliasFlags struct {
+	pairs stringList
+}
+
+// addAliasFlag registers --alias OLD=NEW on fs.
+func addAliasFlag(fs *flag.FlagSet) *aliasFlags {
+	f := &aliasFlags{}
+	fs.Var(&f.pairs, "alias", "Count symbol OLD as NEW, e.g. FB=META (repeatable; adds to TVUE_SYMBOL_ALIASES)")
+	return f
+}
+
+// aliases returns the alias map from TVUE_SYMBOL_ALIASES and the flags, the
+// flags coming last so they win over the environment for the same symbol.
+func (f *aliasFlags) aliases() (map[string]string, error) {
+	return summary.ParseAliases(append(config.ConfiguredAliases(), f.pairs...))
+}
+
+// annotationFlags holds --with-annotations and --flag, which join the
+// local trade annotations written by 'tvue annotate'.
+type annotationFlags struct {
+	with  *bool
+	flags stringList
+}
+
+// addAnnotationFlags registers --with-annotations and --flag on fs.
+func addAnnotationFlags(fs *flag.FlagSet) *annotationFlags {
+	f := &annotationFlags{
+		with: fs.Bool("with-annotations", false, "Join the local flags set with 'tvue annotate' ("+summary.AnnotationsFile+")"),
+	}
+	fs.Var(&f.flags, "flag", "Only include trades with this local flag (repeatable; needs --with-annotations)")
+	return f
+}
+
+// apply loads the annotations of dataDir into opts when --with-annotations
+// is set, along with the --flag filter.
+func (f *annotationFlags) apply(dataDir string, opts *summary.Options) error {
+	if !*f.with {
+		if len(f.flags) > 0 {
+			return fmt.Errorf("--flag needs --with-annotations")
+		}
+		return nil
+	}
+	ann, err := summary.LoadAnnotations(dataDir)
+	if err != nil {
+		return err
+	}
+	opts.Annotations = ann
+	opts.Flags = f.flags
+	return nil
+}
+
+// sinceFlag holds --since, a start date given as a span back from today.
+type sinceFlag struct {
+	span *string
+}
+
+// addSinceFlag registers --since on fs, as an alternative to --from.
+func addSinceFlag(fs *flag.FlagSet) *sinceFlag {
+	return &sinceFlag{span: fs.String("since", "", "Start this long before today, in days, weeks, months, or years (e.g. 30d, 2w, 3m, 1y); instead of --from")}
+}
+
+// apply sets from to the --since date, if given. Giving both is an error.
+func (f *sinceFlag) apply(from *string) error {
+	if *f.span == "" {
+		return nil
+	}
+	if *from != "" {
+		return fmt.Errorf("--since conflicts with --from; use one or the other")
+	}
+	date, err := sinceDate(*f.span, time.Now())
+	if err != nil {
+		return err
+	}
+	*from = date
+	return nil
+}
+
+// sinceDate returns the yyyy-mm-dd date span before now, where span is a
+// count with a d, w, m, or y suffix. Months and years are calendar ones, as
+// time.AddDate counts them, so 1m on March 31 lands in early March.
+func sinceDate(span string, now time.Time) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(span))
+	invalid := fmt.Errorf("invalid --since %q (use a count and d, w, m, or y, e.g. 30d)", span)
+	if len(s) < 2 {
+		return "", invalid
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return "", invalid
+	}
+
+	switch s[len(s)-1] {
+	case 'd':
+		now = now.AddDate(0, 0, -n)
+	case 'w':
+		now = now.AddDate(0, 0, -7*n)
+	case 'm':
+		now = now.AddDate(0, -n, 0)
+	case 'y':
+		now = now.AddDate(-n, 0, 0)
+	default:
+		return "", invalid
+	}
+	return now.Format("2006-01-02"), nil
+}
+
+// stringList is a repeatable flag; each occurrence appends a value, and
+// comma-separated values are split.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(v string) error {
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
+// optionalFloat is a float flag that records whether it was given, so zero
+// and negative values are thresholds like any other.
+type optionalFloat struct {
+	value float64
+	set   bool
+}
+
+func (f *optionalFloat) String() string {
+	if f == nil || !f.set {
+		return ""
+	}
+	return strconv.FormatFloat(f.value, 'f', -1, 64)
+}
+
+func (f *optionalFloat) Set(v string) error {
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("not a number: %q", v)
+	}
+	f.value, f.set = n, true
+	return nil
+}
+
+// ptr returns the value, or nil if the flag wasn't given.
+func (f *optionalFloat) ptr() *float64 {
+	if !f.set {
+		return nil
+	}
+	return &f.value
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jefrnc/tradervue-utils/internal/config"
+	"github.com/jefrnc/tradervue-utils/pkg/exporter"
+)
+
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	csvFile := fs.String("csv", "", "Tradervue CSV download to import (- for stdin)")
+	timezone := fs.String("timezone", "", "Timezone of the CSV's times, and for grouping trades into days (default: America/New_York)")
+	compress := fs.Bool("compress", false, "Write day files gzipped (trades/yyyy-mm-dd.json.gz)")
+	force := fs.Bool("force", false, "Replace day files that already exist (default: skip them)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue import --csv <file> [options]\n\nWrites day files from a CSV of trades downloaded from Tradervue, for\naccounts without API access.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	if *csvFile == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *timezone == "" {
+		*timezone = config.ConfiguredTimezone()
+	}
+
+	var r io.Reader = os.Stdin
+	if *csvFile != "-" {
+		f, err := os.Open(*csvFile)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	exp := exporter.New(nil, dirs.path())
+	result, err := exp.ImportCSV(r, exporter.ImportOptions{Timezone: *timezone, Compress: *compress, Overwrite: *force})
+	if result != nil && result.Written > 0 {
+		log.Printf("Imported %d trades into %d day files in %s", result.Trades, result.Written, dirs.path())
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if len(result.Skipped) > 0 {
+		log.Printf("Skipped %d days that already have a day file (use --force to replace them): %s",
+			len(result.Skipped), strings.Join(result.Skipped, ", "))
+	}
+	if result.Written == 0 && len(result.Skipped) == 0 {
+		log.Printf("No trades found in %s", *csvFile)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/jefrnc/tradervue-utils/pkg/exporter"
+)
+
+func runJournal(args []string) {
+	fs := flag.NewFlagSet("journal", flag.ExitOnError)
+
+	creds := addCredentialFlags(fs)
+	fromDate := fs.String("from", "", "Start date (yyyy-mm-dd, default: first trade date)")
+	toDate := fs.String("to", "", "End date (yyyy-mm-dd)")
+	force := fs.Bool("force", false, "Refresh days that already have a journal entry")
+	quiet := fs.Bool("quiet", false, "Log only the final summary, warnings, and errors")
+	compress := fs.Bool("compress", false, "Write new journal-only day files gzipped")
+
+	// Short aliases
+	fs.BoolVar(quiet, "q", false, "")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue journal [options]\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	cfg, err := creds.load()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	exp := exporter.New(newClient(cfg), cfg.DataDir)
+	exp.SetLogger(newLogger(cfg))
+
+	opts := exporter.Options{
+		FromDate: *fromDate,
+		ToDate:   *toDate,
+		Force:    *force,
+		Quiet:    *quiet,
+		Timezone: cfg.Timezone,
+		Compress: *compress,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := exp.RunJournal(ctx, opts); err != nil {
+		log.Fatalf("Journal export failed: %v", err)
+	}
+}
@@ -1,15 +1,26 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/jefrnc/tradervue-utils/internal/api"
 	"github.com/jefrnc/tradervue-utils/internal/config"
-	"github.com/jefrnc/tradervue-utils/internal/exporter"
-	"github.com/jefrnc/tradervue-utils/internal/summary"
+	"github.com/jefrnc/tradervue-utils/pkg/api"
+	"github.com/jefrnc/tradervue-utils/pkg/exporter"
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
 )
 
 // version is set at build time via ldflags in the release pipeline.
@@ -28,6 +39,40 @@ func main() {
 		runExport(os.Args[2:])
 	case "summary":
 		runSummary(os.Args[2:])
+	case "trades":
+		runTrades(os.Args[2:])
+	case "tags":
+		runTags(os.Args[2:])
+	case "search":
+		runSearch(os.Args[2:])
+	case "notes":
+		runNotes(os.Args[2:])
+	case "annotate":
+		runAnnotate(os.Args[2:])
+	case "taxes":
+		runTaxes(os.Args[2:])
+	case "stats":
+		runStats(os.Args[2:])
+	case "compare":
+		runCompare(os.Args[2:])
+	case "tui":
+		runTUI(os.Args[2:])
+	case "estimate":
+		runEstimate(os.Args[2:])
+	case "journal":
+		runJournal(os.Args[2:])
+	case "db":
+		runDB(os.Args[2:])
+	case "bundle":
+		runBundle(os.Args[2:])
+	case "unbundle":
+		runUnbundle(os.Args[2:])
+	case "import":
+		runImport(os.Args[2:])
+	case "check":
+		runCheck(os.Args[2:])
+	case "doctor":
+		runDoctor(os.Args[2:])
 	case "version":
 		fmt.Printf("tvue v%s\n", version)
 	case "help", "--help", "-h":
@@ -42,18 +87,39 @@ func main() {
 func runExport(args []string) {
 	fs := flag.NewFlagSet("export", flag.ExitOnError)
 
-	username := fs.String("username", "", "Tradervue username")
-	password := fs.String("password", "", "Tradervue password")
-	dataDir := fs.String("data-dir", "", "Data directory (default: ./data)")
+	creds := addCredentialFlags(fs)
 	fromDate := fs.String("from", "", "Start date (yyyy-mm-dd)")
 	toDate := fs.String("to", "", "End date (yyyy-mm-dd)")
+	since := addSinceFlag(fs)
 	withExecs := fs.Bool("with-executions", false, "Fetch individual executions per trade (slower)")
+	withSummary := fs.Bool("with-summary", false, "Also cache each day's summary so 'tvue summary' doesn't reparse trades")
+	compress := fs.Bool("compress", false, "Write day files gzipped (trades/yyyy-mm-dd.json.gz)")
+	closedOnly := fs.Bool("closed-only", false, "Leave open trades out of the day files until they close")
+	includeOpen := fs.Bool("include-open", true, "Save open trades too (the default; --include-open=false is --closed-only)")
 	force := fs.Bool("force", false, "Re-export existing dates")
+	verify := fs.Bool("verify", false, "Find and backfill missing day files between the first and last export")
+	dryRun := fs.Bool("dry-run", false, "Fetch trades and show which day files would be written, without writing")
+	concurrency := fs.Int("concurrency", 4, "Parallel execution fetches with --with-executions")
+	chunkDays := fs.Int("chunk-days", 0, "Fetch and save the range this many days at a time, so a long backfill keeps its progress (default: all at once)")
+	quiet := fs.Bool("quiet", false, "Log only the final summary, warnings, and errors")
+	verbose := fs.Bool("verbose", false, "Also log every page fetched and every trade's executions")
+	reconcileFees := fs.Bool("reconcile-fees", false, "With --with-executions, warn about trades whose commission and fees disagree with their executions")
+	feeTolerance := fs.Float64("fee-tolerance", exporter.DefaultFeeTolerance, "With --reconcile-fees, ignore differences up to this amount")
+	synthesize := fs.Bool("synthesize-executions", false, "With --with-executions, stand in entry and exit executions for trades Tradervue returns none for")
+	failFast := fs.Bool("fail-fast", false, "With --with-executions, stop at the first trade whose executions fail to download (default: save it without them and go on)")
+	notesFile := fs.String("notes", "", "After exporting, write the notes of trades in range to this Markdown file")
+	statsOnExit := fs.Bool("stats-on-exit", false, "Finish with a line of API request counts, bytes, retries, and rate-limit waits")
+	var symbols stringList
+	fs.Var(&symbols, "symbol", "Only export this symbol (repeatable)")
+	var tags stringList
+	fs.Var(&tags, "tag", "Only export trades with this tag (repeatable)")
+	var targets notifyTargets
+	fs.StringVar(&targets.url, "notify-url", "", "POST a JSON report to this URL when the export finishes")
+	fs.StringVar(&targets.slack, "slack-webhook", "", "Post a message to this Slack incoming webhook when the export finishes")
 
 	// Short aliases
-	fs.StringVar(username, "u", "", "")
-	fs.StringVar(password, "p", "", "")
-	fs.StringVar(dataDir, "d", "", "")
+	fs.BoolVar(quiet, "q", false, "")
+	fs.BoolVar(verbose, "v", false, "")
 
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: tvue export [options]\n\nOptions:\n")
@@ -63,38 +129,251 @@ func runExport(args []string) {
 	if err := fs.Parse(args); err != nil {
 		os.Exit(1)
 	}
+	if *quiet && *verbose {
+		log.Fatalf("Error: --quiet conflicts with --verbose; use one or the other")
+	}
+	if err := since.apply(fromDate); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	explicitOpen := false
+	fs.Visit(func(f *flag.Flag) { explicitOpen = explicitOpen || f.Name == "include-open" })
+	if *closedOnly && explicitOpen && *includeOpen {
+		log.Fatalf("Error: --closed-only conflicts with --include-open; use one or the other")
+	}
+	if !*includeOpen {
+		*closedOnly = true
+	}
+	if *reconcileFees && !*withExecs {
+		log.Fatalf("Error: --reconcile-fees compares against executions; add --with-executions")
+	}
+	if *synthesize && !*withExecs {
+		log.Fatalf("Error: --synthesize-executions fills in missing executions; add --with-executions")
+	}
+	if *failFast && !*withExecs {
+		log.Fatalf("Error: --fail-fast applies to execution downloads; add --with-executions")
+	}
+	if *feeTolerance < 0 {
+		log.Fatalf("Error: --fee-tolerance must not be negative")
+	}
+	if *chunkDays < 0 {
+		log.Fatalf("Error: --chunk-days must not be negative")
+	}
 
-	cfg, err := config.Load(*username, *password, *dataDir)
+	cfg, err := creds.load()
 	if err != nil {
 		log.Fatalf("Error: %v", err)
 	}
 
-	client := api.NewClient(cfg.Username, cfg.Password, cfg.UserAgent)
+	client := newClient(cfg)
 	exp := exporter.New(client, cfg.DataDir)
+	exp.SetLogger(newLogger(cfg))
 
 	opts := exporter.Options{
 		WithExecutions: *withExecs,
 		FromDate:       *fromDate,
 		ToDate:         *toDate,
 		Force:          *force,
+		Concurrency:    *concurrency,
+		Symbols:        symbols,
+		Tags:           tags,
+		Verify:         *verify,
+		DryRun:         *dryRun,
+		Quiet:          *quiet,
+		Verbose:        *verbose,
+		Timezone:       cfg.Timezone,
+		WithSummary:    *withSummary,
+		Compress:       *compress,
+		ClosedOnly:     *closedOnly,
+		ReconcileFees:  *reconcileFees,
+		FeeTolerance:   *feeTolerance,
+		NotesFile:      *notesFile,
+		ChunkDays:      *chunkDays,
+
+		SynthesizeExecutions: *synthesize,
+		FailFast:             *failFast,
 	}
 
-	if err := exp.Run(opts); err != nil {
+	// Ctrl-C stops pagination and lets the exporter record completed days.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := exp.Run(ctx, opts)
+	if *statsOnExit {
+		log.Printf("API stats: %s", formatStats(client.Stats()))
+	}
+	if !opts.DryRun {
+		targets.send(cfg.DataDir, report, err)
+	}
+	if err != nil {
 		log.Fatalf("Export failed: %v", err)
 	}
+	if n := len(report.FeeMismatches); n > 0 {
+		log.Printf("%d trades have commission and fees that disagree with their executions (see warnings above).", n)
+	}
+	if n := len(report.ExecutionGaps); n > 0 {
+		if opts.SynthesizeExecutions {
+			log.Printf("%d trades came back without executions; they were given entry and exit executions instead.", n)
+		} else {
+			log.Printf("%d trades came back without executions (see warnings above). Add --synthesize-executions to fill them in from each trade's entry and exit.", n)
+		}
+	}
+
+	if n := len(report.FailedExecutions); n > 0 {
+		log.Printf("%d trades were saved without executions because fetching them failed: %s. Add --fail-fast to stop at the first failure instead.", n, joinIDs(report.FailedExecutions))
+	}
+
+	// Partial failures exit 2, so scripts can tell them from a clean run (0)
+	// and a failed one (1)
+	if !report.OK() {
+		log.Printf("Export finished with %d problems.", len(report.Problems))
+		if dates := report.Dates(); len(dates) > 0 {
+			log.Printf("Affected dates: %s", strings.Join(dates, ", "))
+			log.Printf("Retry them with: tvue export --from <date> --to <date> --force")
+		}
+		os.Exit(2)
+	}
+}
+
+// joinIDs lists trade IDs separated by commas.
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatStats renders the client's traffic counts as one line.
+func formatStats(s api.Stats) string {
+	return fmt.Sprintf("%d requests, %s received, %d retries, %d throttled (HTTP 429), %s waiting on the request delay, %s backing off",
+		s.Requests, formatBytes(s.Bytes), s.Retries, s.Throttled,
+		s.RateLimitWait.Round(time.Millisecond), s.RetryWait.Round(time.Millisecond))
+}
+
+// newClient builds an API client using token auth when a token is
+// configured, falling back to username/password.
+func newClient(cfg *config.Config) *api.Client {
+	var auth api.Authenticator = api.BasicAuth{Username: cfg.Username, Password: cfg.Password}
+	if cfg.UsesToken() {
+		auth = api.TokenAuth{Token: cfg.Token}
+	}
+
+	var opts []api.Option
+	if cfg.RequestDelay > 0 {
+		opts = append(opts, api.WithRequestDelay(cfg.RequestDelay))
+	}
+	if cfg.MaxRetries > 0 {
+		opts = append(opts, api.WithMaxRetries(cfg.MaxRetries))
+	}
+	if cfg.MaxFailures > 0 {
+		opts = append(opts, api.WithMaxFailures(cfg.MaxFailures))
+	}
+	if cfg.RetryBase > 0 || cfg.RetryMax > 0 {
+		opts = append(opts, api.WithBackoff(cfg.RetryBase, cfg.RetryMax))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, api.WithTimeout(cfg.Timeout))
+	}
+	if cfg.CACert != "" {
+		pool, err := api.LoadCACert(cfg.CACert)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		opts = append(opts, api.WithRootCAs(pool))
+	}
+	if cfg.Debug {
+		opts = append(opts, api.WithDebug(), api.WithLogger(newLogger(cfg)))
+	}
+	return api.NewClient(auth, cfg.UserAgent, opts...)
+}
+
+// logOutput is where log messages go: stderr, teed to --log-file when one
+// is given.
+var logOutput io.Writer = os.Stderr
+
+// newLogger returns the logger for the exporter and API client: JSON lines
+// with --log-json, otherwise plain text on stderr (and --log-file).
+func newLogger(cfg *config.Config) logging.Logger {
+	if cfg.LogJSON {
+		return logging.FromSlog(slog.Default())
+	}
+	return logging.NewText(logOutput)
+}
+
+// warnTimezoneMismatch warns when the data in dataDir was grouped into days
+// by a different timezone than the one now configured, so summaries would
+// mix day boundaries with those of future exports.
+func warnTimezoneMismatch(dataDir string) {
+	state, err := exporter.LoadState(dataDir)
+	if err != nil || state == nil || state.Timezone == "" {
+		return
+	}
+	tz := config.ConfiguredTimezone()
+	if tz == "" {
+		tz = exporter.DefaultTimezone
+	}
+	if tz != state.Timezone {
+		log.Printf("Warning: this data was grouped into days by %s, but the configured timezone is %s; "+
+			"re-export with --force (without --from, --to, --symbol, or --tag) to regroup it, or set TVUE_TIMEZONE=%s", state.Timezone, tz, state.Timezone)
+	}
+}
+
+// dataLocation returns the zone the data in dataDir was grouped into days
+// by: the one its exports recorded, else the configured or default zone.
+func dataLocation(dataDir string) *time.Location {
+	tz := config.ConfiguredTimezone()
+	if state, err := exporter.LoadState(dataDir); err == nil && state != nil && state.Timezone != "" {
+		tz = state.Timezone
+	}
+	if tz == "" {
+		tz = exporter.DefaultTimezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil
+	}
+	return loc
 }
 
 func runSummary(args []string) {
 	fs := flag.NewFlagSet("summary", flag.ExitOnError)
 
-	dataDir := fs.String("data-dir", "./data", "Data directory")
+	dirs := addDataDirFlags(fs)
 	fromDate := fs.String("from", "", "Start date filter (yyyy-mm-dd)")
 	toDate := fs.String("to", "", "End date filter (yyyy-mm-dd)")
-	csvOutput := fs.Bool("csv", false, "Output as CSV")
+	since := addSinceFlag(fs)
+	csvOutput := fs.Bool("csv", false, "Output as CSV (same as --format csv)")
+	format := fs.String("format", "", "Output format: table, csv, json, md, calendar, xlsx, prom, or equity (also equity-csv, equity-json) (default: table)")
 	outputFile := fs.String("output", "", "Output file (default: stdout)")
+	fieldsFlag := fs.String("fields", "", "With --format csv, comma-separated columns to write, in order (e.g. date,net_pl,win_rate)")
+	appendCSV := fs.Bool("append", false, "With --format csv and --output, only add rows newer than the file's last row")
+	outputDir := fs.String("output-dir", "", "With --split-by, directory to write one file per period into")
+	splitByFlag := fs.String("split-by", "", "Write one file per week, month, or year into --output-dir")
+	groupBy := fs.String("group-by", "day", "Group rows by day, week, month, or year")
+	dateFormat := fs.String("date-format", "", "Date display in table and CSV output: iso, us, eu, or a Go layout like 01/02/2006")
+	scratch := fs.Float64("scratch-threshold", 0, "Count trades with |P&L| up to this amount as scratches")
+	winBasis := fs.String("win-basis", "gross", "P&L that decides winners and losers: gross, or net of commission and fees")
+	currency := fs.String("currency", "", "Only include trades made in this currency (e.g. USD)")
+	var symbols stringList
+	fs.Var(&symbols, "symbol", "Only include this symbol (repeatable)")
+	var excludeSymbols stringList
+	fs.Var(&excludeSymbols, "exclude-symbol", "Leave out this symbol, even if also given with --symbol (repeatable)")
+	var tags stringList
+	fs.Var(&tags, "tag", "Only include trades with this tag (repeatable)")
+	tagMode := fs.String("tag-mode", "any", "With several --tag flags, match trades with any or all of them")
+	aliases := addAliasFlag(fs)
+	annotations := addAnnotationFlags(fs)
+	minTrades := fs.Int("min-trades", 0, "Only show rows with at least this many trades")
+	var minNet, maxNet optionalFloat
+	fs.Var(&minNet, "min-net", "Only show rows with net P&L of at least this amount (may be negative)")
+	fs.Var(&maxNet, "max-net", "Only show rows with net P&L of at most this amount (may be negative)")
+	totals := fs.String("totals", "filtered", "Totals row sums the rows shown (filtered) or every row before --min-*/--max-net (all)")
+	strict := fs.Bool("strict", false, "Fail on a day file whose contents disagree with its filename date, instead of warning")
+	redact := fs.Bool("redact", false, "Show P&L in units of the average day's absolute net P&L instead of dollars, for sharing")
+	exposure := fs.Bool("exposure", false, "Add columns for total notional (entry price x volume) and the largest position's")
 
 	// Short aliases
-	fs.StringVar(dataDir, "d", "./data", "")
 	fs.StringVar(outputFile, "o", "", "")
 
 	fs.Usage = func() {
@@ -105,10 +384,140 @@ func runSummary(args []string) {
 	if err := fs.Parse(args); err != nil {
 		os.Exit(1)
 	}
+	if err := since.apply(fromDate); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
 
-	gen := summary.NewGenerator(*dataDir)
+	if *csvOutput {
+		if *format != "" && *format != "csv" {
+			log.Fatalf("Error: --csv conflicts with --format %s; use one or the other", *format)
+		}
+		*format = "csv"
+	}
+	if *scratch < 0 {
+		log.Fatalf("Error: --scratch-threshold must not be negative")
+	}
+	basis, err := summary.ParseWinBasis(*winBasis)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if *tagMode != "any" && *tagMode != "all" {
+		log.Fatalf("Error: unknown --tag-mode %q (use any or all)", *tagMode)
+	}
+	switch *format {
+	case "", "table", "csv", "json", "md", "calendar", "xlsx", "prom", "equity", "equity-csv", "equity-json":
+	case "excel":
+		*format = "xlsx"
+	case "markdown":
+		*format = "md"
+	default:
+		log.Fatalf("Error: unknown format %q (use table, csv, json, md, calendar, xlsx, prom, equity, equity-csv, or equity-json)", *format)
+	}
+	if *redact {
+		switch *format {
+		case "", "table", "csv", "json", "md":
+		default:
+			log.Fatalf("Error: --redact works with table, csv, json, and md output")
+		}
+	}
+	if *exposure {
+		switch *format {
+		case "", "table", "csv", "json", "md":
+		default:
+			log.Fatalf("Error: --exposure works with table, csv, json, and md output")
+		}
+	}
+	var fields []string
+	if *fieldsFlag != "" {
+		if *format != "csv" {
+			log.Fatalf("Error: --fields picks CSV columns; use it with --format csv")
+		}
+		if fields, err = summary.ParseFields(*fieldsFlag); err != nil {
+			log.Fatalf("Error: --fields: %v", err)
+		}
+	}
+	if *appendCSV {
+		switch {
+		case *format != "csv":
+			log.Fatalf("Error: --append adds rows to a CSV file; use it with --format csv")
+		case *outputFile == "":
+			log.Fatalf("Error: --append needs the CSV file to add to; give it with --output")
+		case *redact:
+			log.Fatalf("Error: --append can't be combined with --redact; each run would scale P&L by a different unit")
+		case fields != nil && !slices.Contains(fields, "date"):
+			log.Fatalf("Error: --append needs the date column to tell which rows are new; add date to --fields")
+		}
+	}
+	if *format == "xlsx" && *outputFile == "" && *outputDir == "" {
+		log.Fatalf("Error: --format xlsx writes a binary workbook; give a file with --output")
+	}
+	if (*splitByFlag == "") != (*outputDir == "") {
+		log.Fatalf("Error: --split-by and --output-dir go together; give both")
+	}
+	if *outputDir != "" && *outputFile != "" {
+		log.Fatalf("Error: --output-dir conflicts with --output; use one or the other")
+	}
+	if *format == "calendar" && *groupBy != "day" {
+		log.Fatalf("Error: --format calendar has one entry per day and can't be combined with --group-by %s", *groupBy)
+	}
+	if *minTrades < 0 {
+		log.Fatalf("Error: --min-trades must not be negative")
+	}
+	if minNet.set && maxNet.set && minNet.value > maxNet.value {
+		log.Fatalf("Error: --min-net %s is above --max-net %s; no row can match", minNet.String(), maxNet.String())
+	}
+	if *totals != "filtered" && *totals != "all" {
+		log.Fatalf("Error: unknown --totals %q (use filtered or all)", *totals)
+	}
+	aliasMap, err := aliases.aliases()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
 
-	summaries, err := gen.Generate(*fromDate, *toDate)
+	period, err := summary.ParsePeriod(*groupBy)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	dateLayout, err := summary.ParseDateFormat(*dateFormat)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	var splitBy summary.Period
+	if *splitByFlag != "" {
+		if splitBy, err = summary.ParsePeriod(*splitByFlag); err != nil {
+			log.Fatalf("Error: --split-by: %v", err)
+		}
+		if splitBy == summary.PeriodDay {
+			log.Fatalf("Error: --split-by day would write a file per row; use week, month, or year")
+		}
+		if !period.FitsIn(splitBy) {
+			log.Fatalf("Error: --group-by %s rows don't fit in --split-by %s files", period, splitBy)
+		}
+	}
+
+	gen := summary.NewGenerator(dirs.path())
+	gen.SetTimezone(dataLocation(dirs.path()))
+	gen.SetStrict(*strict)
+	warnTimezoneMismatch(dirs.path())
+
+	opts := summary.Options{
+		FromDate: *fromDate,
+		ToDate:   *toDate,
+		Symbols:  symbols,
+
+		ExcludeSymbols:   excludeSymbols,
+		ScratchThreshold: *scratch,
+		WinBasis:         basis,
+		Currency:         *currency,
+		Tags:             tags,
+		MatchAllTags:     *tagMode == "all",
+		Aliases:          aliasMap,
+	}
+	if err := annotations.apply(dirs.path(), &opts); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	summaries, err := gen.Generate(opts)
 	if err != nil {
 		log.Fatalf("Error: %v", err)
 	}
@@ -118,26 +527,144 @@ func runSummary(args []string) {
 		return
 	}
 
-	// Determine output writer
-	var w *os.File
-	if *outputFile != "" {
-		f, err := os.Create(*outputFile)
+	ro := summary.RenderOptions{Period: period, DateLayout: dateLayout, Redacted: *redact, Exposure: *exposure, Fields: fields}
+	rows := summary.RowFilter{MinTrades: *minTrades, MinNetPL: minNet.ptr(), MaxNetPL: maxNet.ptr()}
+	unit := summary.RedactionUnit(summaries)
+
+	if splitBy != "" {
+		if err := os.MkdirAll(*outputDir, 0755); err != nil {
+			log.Fatalf("Error creating output directory: %v", err)
+		}
+		for _, part := range summary.Split(summaries, splitBy) {
+			shown, ro := filterRows(summary.Rollup(part.Summaries, period), ro, rows, *totals)
+			shown, ro = redactRows(shown, ro, unit)
+			if len(shown) == 0 {
+				continue
+			}
+			path := filepath.Join(*outputDir, part.Label+summaryExt(*format))
+			if err := writeSummaryFile(path, gen, *format, shown, ro, "", ""); err != nil {
+				log.Fatalf("Error writing %s: %v", path, err)
+			}
+			log.Printf("Wrote %s (%d rows)", path, len(shown))
+		}
+		return
+	}
+
+	summaries, ro = filterRows(summary.Rollup(summaries, period), ro, rows, *totals)
+	if len(summaries) == 0 {
+		log.Println("No rows match the --min-trades/--min-net/--max-net thresholds.")
+		return
+	}
+	summaries, ro = redactRows(summaries, ro, unit)
+
+	if *appendCSV {
+		n, last, err := gen.AppendCSV(*outputFile, summaries, ro)
 		if err != nil {
-			log.Fatalf("Error creating output file: %v", err)
+			log.Fatalf("Error appending to %s: %v", *outputFile, err)
+		}
+		switch {
+		case last == "":
+			log.Printf("Wrote %s (%d rows)", *outputFile, n)
+		case n == 0:
+			log.Printf("%s is up to date (last row %s)", *outputFile, last)
+		default:
+			log.Printf("Appended %d rows to %s (after %s)", n, *outputFile, last)
 		}
-		defer f.Close()
-		w = f
-	} else {
-		w = os.Stdout
+		return
 	}
 
-	if *csvOutput {
-		if err := gen.ExportCSV(w, summaries); err != nil {
-			log.Fatalf("Error writing CSV: %v", err)
+	if *outputFile != "" {
+		if err := writeSummaryFile(*outputFile, gen, *format, summaries, ro, *fromDate, *toDate); err != nil {
+			log.Fatalf("Error writing %s: %v", *outputFile, err)
 		}
-	} else {
-		gen.PrintTable(w, summaries)
+		return
+	}
+	if err := writeSummary(os.Stdout, gen, *format, summaries, ro, *fromDate, *toDate); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+// filterRows applies the --min-trades/--min-net/--max-net thresholds to
+// rolled-up summaries, pointing the totals row at every row when --totals
+// is all.
+func filterRows(summaries []models.DailySummary, ro summary.RenderOptions, rows summary.RowFilter, totals string) ([]models.DailySummary, summary.RenderOptions) {
+	if !rows.Active() {
+		return summaries, ro
+	}
+	if totals == "all" {
+		ro.TotalsOver = summaries
+	}
+	return rows.Apply(summaries), ro
+}
+
+// redactRows scales the P&L of the shown rows, and of the rows the totals
+// are taken over, to unit when --redact is set.
+func redactRows(summaries []models.DailySummary, ro summary.RenderOptions, unit float64) ([]models.DailySummary, summary.RenderOptions) {
+	if !ro.Redacted {
+		return summaries, ro
+	}
+	if ro.TotalsOver != nil {
+		ro.TotalsOver = summary.Redact(ro.TotalsOver, unit)
+	}
+	return summary.Redact(summaries, unit), ro
+}
+
+// summaryExt is the file extension for a summary --format.
+func summaryExt(format string) string {
+	switch format {
+	case "csv", "equity-csv":
+		return ".csv"
+	case "json", "calendar", "equity-json":
+		return ".json"
+	case "xlsx":
+		return ".xlsx"
+	case "prom":
+		return ".prom"
+	case "md":
+		return ".md"
+	}
+	return ".txt"
+}
+
+// writeSummaryFile writes summaries to path in the given format.
+func writeSummaryFile(path string, gen *summary.Generator, format string, summaries []models.DailySummary, ro summary.RenderOptions, from, to string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeSummary(f, gen, format, summaries, ro, from, to); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeSummary writes summaries to w in the given --format. from and to
+// bound the zero-filled calendar; empty means the data's own range.
+func writeSummary(w io.Writer, gen *summary.Generator, format string, summaries []models.DailySummary, ro summary.RenderOptions, from, to string) error {
+	switch format {
+	case "csv":
+		return gen.ExportCSV(w, summaries, ro)
+	case "json":
+		return gen.ExportJSON(w, summaries, ro)
+	case "md":
+		return gen.ExportMarkdown(w, summaries, ro)
+	case "calendar":
+		return gen.ExportCalendar(w, summaries, from, to)
+	case "xlsx":
+		return gen.ExportXLSX(w, summaries, ro)
+	case "prom":
+		return gen.ExportProm(w, summaries, ro)
+	case "equity":
+		gen.PrintEquity(w, summary.Equity(summaries), ro)
+	case "equity-csv":
+		return gen.ExportEquityCSV(w, summary.Equity(summaries), ro)
+	case "equity-json":
+		return gen.ExportEquityJSON(w, summary.Equity(summaries))
+	default:
+		gen.PrintTable(w, summaries, ro)
 	}
+	return nil
 }
 
 func printUsage() {
@@ -151,6 +678,23 @@ Usage:
 Commands:
   export    Export trades from Tradervue API
   summary   Show daily trade summaries from exported data
+  trades    List individual trades from exported data
+  tags      Show net P&L and win rate per tag
+  search    Find trades whose notes mention a phrase
+  notes     Collect trade notes into one Markdown document
+  annotate  Flag trades locally (mistake, a+ setup) for review
+  taxes     List realized gains per closed trade (Form 8949 style)
+  stats     Show lifetime metrics across all exported data
+  compare   Compare two date ranges side by side
+  tui       Browse summaries and trades in an interactive dashboard
+  estimate  Estimate request count, time, and disk usage of an export
+  journal   Export daily journal entries into the day files
+  check     Check day files against their recorded checksums
+  doctor    Diagnose credentials, API access, and the data directory
+  db        Import exported data into a SQLite database (db import)
+  bundle    Combine the day files into one JSON file
+  unbundle  Split a bundle back into day files
+  import    Write day files from a Tradervue CSV download (no API needed)
   version   Print version
   help      Show this help
 
@@ -158,13 +702,33 @@ Examples:
   tvue export -u myuser -p mypass          # First run (full export)
   tvue export                              # Incremental (uses .env)
   tvue export --from 2025-01-01 --force    # Re-export range
+  tvue export --verify                     # Backfill missing days
+  tvue estimate --with-executions          # Size up an export first
   tvue summary                             # Show all summaries
   tvue summary --from 2025-01-01 --csv     # CSV output
+  tvue summary --group-by month            # Monthly rollup
+  tvue summary --format json               # JSON for other tools
+  tvue trades --csv -o trades.csv          # One row per trade
+  tvue trades --format jsonl               # JSONL for pipelines
+  tvue summary --tag news                  # Only trades tagged "news"
+  tvue tags                                # Per-tag breakdown
+  tvue search "gap and go"                 # Trades whose notes match
+  tvue notes -o notes.md                   # Every trade's notes in one file
+  tvue annotate 123456 --flag mistake      # Flag a trade locally
+  tvue tags --with-annotations --by-flag   # Per-flag breakdown
+  tvue stats                               # Lifetime metrics
+  tvue compare --a 2025-01-01:2025-01-31 --b 2025-02-01:2025-02-28
+  tvue tui                                 # Interactive dashboard
+  tvue taxes --year 2024 --csv             # Realized gains for 2024
+  tvue db import --db trades.db            # Load into SQLite
+  tvue bundle -o all.json.gz               # Every day in one file
+  tvue import --csv trades.csv             # Day files from a CSV download
 
 Configuration:
   Credentials via flags (--username, --password) or .env file:
     TRADERVUE_USERNAME=your_username
     TRADERVUE_PASSWORD=your_password
+  Or an API token via --token or TRADERVUE_API_TOKEN (takes precedence).
 
 `, version)
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+func runNotes(args []string) {
+	fs := flag.NewFlagSet("notes", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	fromDate := fs.String("from", "", "Start date filter (yyyy-mm-dd)")
+	toDate := fs.String("to", "", "End date filter (yyyy-mm-dd)")
+	outputFile := fs.String("output", "", "Output file (default: stdout)")
+	var symbols stringList
+	fs.Var(&symbols, "symbol", "Only include this symbol (repeatable)")
+	aliases := addAliasFlag(fs)
+
+	// Short aliases
+	fs.StringVar(outputFile, "o", "", "")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue notes [options]\n\nWrites the notes of every trade that has them as one Markdown document,\nwith a section per day.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	aliasMap, err := aliases.aliases()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	gen := summary.NewGenerator(dirs.path())
+
+	rows, err := gen.Trades(summary.Options{
+		FromDate: *fromDate,
+		ToDate:   *toDate,
+		Symbols:  symbols,
+		Aliases:  aliasMap,
+	})
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if len(rows) == 0 {
+		log.Println("No trades found. Run 'tvue export' first.")
+		return
+	}
+	if len(summary.WithNotes(rows)) == 0 {
+		log.Println("None of the selected trades have notes.")
+		return
+	}
+
+	// Determine output writer
+	var w *os.File
+	if *outputFile != "" {
+		f, err := os.Create(*outputFile)
+		if err != nil {
+			log.Fatalf("Error creating output file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	} else {
+		w = os.Stdout
+	}
+
+	n, err := gen.ExportNotesMarkdown(w, rows)
+	if err != nil {
+		log.Fatalf("Error writing notes: %v", err)
+	}
+	if *outputFile != "" {
+		log.Printf("Wrote notes of %d trades to %s", n, *outputFile)
+	}
+}
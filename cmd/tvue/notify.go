@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/notify"
+	"github.com/jefrnc/tradervue-utils/pkg/exporter"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+// notifyTargets are the webhooks to tell about a finished export.
+type notifyTargets struct {
+	url   string // generic JSON webhook
+	slack string // Slack incoming webhook
+}
+
+func (n notifyTargets) empty() bool {
+	return n.url == "" && n.slack == ""
+}
+
+// send reports the outcome of an export to the configured webhooks. It is
+// best-effort: failures are logged and never change the export's result.
+func (n notifyTargets) send(dataDir string, report *exporter.Report, runErr error) {
+	if n.empty() {
+		return
+	}
+
+	p := exportPayload(dataDir, report, runErr)
+
+	// The export's own context may already be cancelled by Ctrl-C
+	ctx := context.Background()
+	if n.url != "" {
+		if err := notify.Post(ctx, n.url, p); err != nil {
+			log.Printf("Warning: notification to --notify-url failed: %v", err)
+		}
+	}
+	if n.slack != "" {
+		if err := notify.PostSlack(ctx, n.slack, p); err != nil {
+			log.Printf("Warning: Slack notification failed: %v", err)
+		}
+	}
+}
+
+// exportPayload summarizes an export run for a notification.
+func exportPayload(dataDir string, report *exporter.Report, runErr error) notify.Payload {
+	p := notify.Payload{
+		Status:   notify.StatusOK,
+		Dates:    []string{},
+		Finished: time.Now(),
+	}
+	if report != nil {
+		p.Dates = append(p.Dates, report.Exported...)
+		p.Days = len(report.Exported)
+		p.Trades = report.Trades
+		for _, prob := range report.Problems {
+			msg := prob.Message
+			if prob.Date != "" {
+				msg = prob.Date + ": " + msg
+			}
+			p.Warnings = append(p.Warnings, msg)
+		}
+	}
+
+	switch {
+	case runErr != nil:
+		p.Status = notify.StatusError
+		p.Error = runErr.Error()
+	case !report.OK():
+		p.Status = notify.StatusWarning
+	}
+
+	if p.Days > 0 {
+		p.NetPL = exportedNetPL(dataDir, p.Dates)
+	}
+	return p
+}
+
+// exportedNetPL totals the realized net P&L of the given day files.
+func exportedNetPL(dataDir string, dates []string) float64 {
+	written := make(map[string]bool, len(dates))
+	from, to := dates[0], dates[0]
+	for _, d := range dates {
+		written[d] = true
+		from = min(from, d)
+		to = max(to, d)
+	}
+
+	summaries, err := summary.NewGenerator(dataDir).Generate(summary.Options{FromDate: from, ToDate: to})
+	if err != nil {
+		return 0
+	}
+	total := 0.0
+	for _, s := range summaries {
+		if written[s.Date] {
+			total += s.NetPL
+		}
+	}
+	return total
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+func runSearch(args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	fromDate := fs.String("from", "", "Start date filter (yyyy-mm-dd)")
+	toDate := fs.String("to", "", "End date filter (yyyy-mm-dd)")
+	regex := fs.Bool("regex", false, "Treat the query as a regular expression")
+	var symbols stringList
+	fs.Var(&symbols, "symbol", "Only search trades in this symbol (repeatable)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue search [options] <query>\n\nLists trades whose notes contain the query (case-insensitive).\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	// Flags may come before or after the query, so parse past each word
+	var words []string
+	for rest := args; ; {
+		if err := fs.Parse(rest); err != nil {
+			os.Exit(1)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		words = append(words, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+
+	// Several words without quotes still search for the phrase
+	query := strings.Join(words, " ")
+	if strings.TrimSpace(query) == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	re, err := summary.SearchPattern(query, *regex)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	gen := summary.NewGenerator(dirs.path())
+
+	rows, err := gen.Trades(summary.Options{
+		FromDate: *fromDate,
+		ToDate:   *toDate,
+		Symbols:  symbols,
+	})
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if len(rows) == 0 {
+		log.Println("No trades found. Run 'tvue export' first.")
+		return
+	}
+
+	matches := summary.Search(rows, re)
+	if len(matches) == 0 {
+		log.Printf("No trade notes match %q.", query)
+		return
+	}
+
+	gen.PrintSearch(os.Stdout, matches)
+}
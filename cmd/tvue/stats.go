@@ -0,0 +1,352 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/config"
+	"github.com/jefrnc/tradervue-utils/pkg/exporter"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/stats"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+func runStats(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	fromDate := fs.String("from", "", "Start date filter (yyyy-mm-dd)")
+	toDate := fs.String("to", "", "End date filter (yyyy-mm-dd)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	byWeekday := fs.Bool("by-weekday", false, "Break net P&L and win rate down by day of the week")
+	byHour := fs.Bool("by-hour", false, "Break P&L and win rate down by the hour each trade started")
+	timezone := fs.String("timezone", "", "With --by-hour, zone to read start times in (default: TVUE_TIMEZONE or America/New_York)")
+	mfe := fs.Bool("mfe", false, "Show MFE/MAE efficiency: capture ratio of winners and average adverse excursion")
+	consistency := fs.Bool("consistency", false, "Show day-level consistency: green-day rate, win/loss streaks, average green/red day, profit factor")
+	scratch := fs.Float64("scratch-threshold", 0, "Count trades with |P&L| up to this amount as scratches")
+	winBasis := fs.String("win-basis", "gross", "P&L that decides winners and losers: gross, or net of commission and fees")
+	currency := fs.String("currency", "", "Only include trades made in this currency (e.g. USD)")
+	aliases := addAliasFlag(fs)
+	benchmark := fs.String("benchmark", "", "Compare with buying and holding this symbol; closes come from --benchmark-csv")
+	benchmarkCSV := fs.String("benchmark-csv", "", "CSV of date,close for the benchmark (Tradervue doesn't provide quotes)")
+	capital := fs.Float64("capital", 0, "With --benchmark or --drawdown, account size that turns net P&L into a return")
+	drawdown := fs.Bool("drawdown", false, "Show the deepest drawdowns of cumulative net P&L and the current one")
+	top := fs.Int("top", 5, "With --drawdown, how many of the deepest drawdowns to list (0 for all)")
+	frequency := fs.Bool("frequency", false, "Show trades per active day, the busiest and quietest days, and whether trading is getting more frequent")
+	activity := fs.Bool("activity", false, "Show days traded out of the market days in range (weekends and holidays excluded)")
+	holidays := fs.String("holidays", "", "With --activity, CSV of date,name market holidays to use instead of the US calendar")
+	symbolHistogram := fs.Bool("symbol-histogram", false, "Show trading days, trades, and net P&L per symbol, most traded first")
+	symbolEquity := fs.Bool("symbol-equity", false, "Write each symbol's equity curve (cumulative net P&L by day) to a CSV in --output")
+	outputDir := fs.String("output", "", "With --symbol-equity, directory to write one CSV per symbol into")
+	minOccurrences := fs.Int("min-occurrences", 1, "With --symbol-equity, skip symbols traded on fewer than this many days")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue stats [options]\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	views := 0
+	for _, v := range []bool{*byWeekday, *byHour, *mfe, *consistency, *benchmarkCSV != "", *symbolHistogram, *symbolEquity, *drawdown, *activity, *frequency} {
+		if v {
+			views++
+		}
+	}
+	if views > 1 {
+		log.Fatalf("Error: --by-weekday, --by-hour, --mfe, --consistency, --benchmark, --symbol-histogram, --symbol-equity, --drawdown, --activity, and --frequency are separate views; use one at a time")
+	}
+	if *holidays != "" && !*activity {
+		log.Fatalf("Error: --holidays is the --activity calendar; add --activity")
+	}
+	if *top < 0 {
+		log.Fatalf("Error: --top must not be negative")
+	}
+	if *symbolEquity && *outputDir == "" {
+		log.Fatalf("Error: --symbol-equity writes a CSV per symbol; give a directory with --output")
+	}
+	if *outputDir != "" && !*symbolEquity {
+		log.Fatalf("Error: --output is the --symbol-equity directory; add --symbol-equity")
+	}
+	if *minOccurrences < 1 {
+		log.Fatalf("Error: --min-occurrences must be at least 1")
+	}
+	if *benchmark != "" && *benchmarkCSV == "" {
+		log.Fatalf("Error: --benchmark needs --benchmark-csv with the symbol's daily closes; Tradervue doesn't provide quotes")
+	}
+	if *capital < 0 {
+		log.Fatalf("Error: --capital must not be negative")
+	}
+	if *scratch < 0 {
+		log.Fatalf("Error: --scratch-threshold must not be negative")
+	}
+	basis, err := summary.ParseWinBasis(*winBasis)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	aliasMap, err := aliases.aliases()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	gen := summary.NewGenerator(dirs.path())
+
+	if *byHour {
+		runByHour(gen, summary.Options{
+			FromDate: *fromDate,
+			ToDate:   *toDate,
+
+			ScratchThreshold: *scratch,
+			WinBasis:         basis,
+			Currency:         *currency,
+			Aliases:          aliasMap,
+		}, *timezone, *jsonOutput)
+		return
+	}
+
+	summaries, err := gen.Generate(summary.Options{
+		FromDate: *fromDate,
+		ToDate:   *toDate,
+
+		ScratchThreshold: *scratch,
+		WinBasis:         basis,
+		Currency:         *currency,
+		Aliases:          aliasMap,
+	})
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	if len(summaries) == 0 {
+		log.Println("No exported data found. Run 'tvue export' first.")
+		return
+	}
+
+	if *byWeekday {
+		days := stats.ByWeekday(summaries)
+		if *jsonOutput {
+			writeJSON(days)
+			return
+		}
+		stats.PrintWeekdays(os.Stdout, days)
+		return
+	}
+
+	if *symbolHistogram {
+		h := stats.BySymbol(summaries)
+		if *jsonOutput {
+			writeJSON(h)
+			return
+		}
+		stats.PrintSymbolHistogram(os.Stdout, h)
+		return
+	}
+
+	if *symbolEquity {
+		writeSymbolEquity(gen, summaries, *outputDir, *minOccurrences)
+		return
+	}
+
+	if *benchmarkCSV != "" {
+		runBenchmark(summaries, *benchmark, *benchmarkCSV, *capital, *jsonOutput)
+		return
+	}
+
+	if *drawdown {
+		r := stats.ComputeDrawdowns(summaries, *capital, *top)
+		if *jsonOutput {
+			writeJSON(r)
+			return
+		}
+		stats.PrintDrawdowns(os.Stdout, r)
+		return
+	}
+
+	if *activity {
+		runActivity(summaries, *holidays, *fromDate, *toDate, *jsonOutput)
+		return
+	}
+
+	if *frequency {
+		f := stats.ComputeFrequency(summaries)
+		if *jsonOutput {
+			writeJSON(f)
+			return
+		}
+		stats.PrintFrequency(os.Stdout, f)
+		return
+	}
+
+	if *consistency {
+		c := stats.ComputeConsistency(summaries)
+		if *jsonOutput {
+			writeJSON(c)
+			return
+		}
+		stats.PrintConsistency(os.Stdout, c)
+		return
+	}
+
+	lifetime := stats.Compute(summaries)
+
+	if *jsonOutput {
+		writeJSON(lifetime)
+		return
+	}
+
+	if *mfe {
+		stats.PrintEfficiency(os.Stdout, lifetime)
+		return
+	}
+	stats.PrintLifetime(os.Stdout, lifetime)
+}
+
+// runByHour prints performance by the hour trades started, read in the
+// named zone. Winners are judged as opts says.
+func runByHour(gen *summary.Generator, opts summary.Options, tz string, jsonOutput bool) {
+	if tz == "" {
+		tz = config.ConfiguredTimezone()
+	}
+	if tz == "" {
+		tz = exporter.DefaultTimezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		log.Fatalf("Error: unknown timezone %q: %v", tz, err)
+	}
+
+	rows, err := gen.Trades(opts)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if len(rows) == 0 {
+		log.Println("No exported data found. Run 'tvue export' first.")
+		return
+	}
+
+	hours := stats.ByHour(rows, loc, opts.ScratchThreshold, opts.WinBasis)
+	if jsonOutput {
+		writeJSON(hours)
+		return
+	}
+	stats.PrintHours(os.Stdout, hours, loc)
+}
+
+// writeSymbolEquity writes the equity curve of each symbol traded on at
+// least minDays days to dir/<symbol>.csv.
+func writeSymbolEquity(gen *summary.Generator, summaries []models.DailySummary, dir string, minDays int) {
+	curves := summary.SymbolEquity(summaries, minDays)
+	if len(curves) == 0 {
+		log.Printf("No symbol was traded on %d or more days.", minDays)
+		return
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Fatalf("Error creating output directory: %v", err)
+	}
+
+	symbols := make([]string, 0, len(curves))
+	for sym := range curves {
+		symbols = append(symbols, sym)
+	}
+	sort.Strings(symbols)
+
+	for _, sym := range symbols {
+		c := curves[sym]
+		path := filepath.Join(dir, fileSafe(sym)+".csv")
+		f, err := os.Create(path)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if err := gen.ExportEquityCSV(f, c, summary.RenderOptions{}); err != nil {
+			f.Close()
+			log.Fatalf("Error writing %s: %v", path, err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatalf("Error writing %s: %v", path, err)
+		}
+		log.Printf("Wrote %s (%d days, net %+.2f)", path, len(c.Points), c.Points[len(c.Points)-1].Equity)
+	}
+}
+
+// fileSafe replaces the characters of a symbol that can't go in a file
+// name, such as the slash in BRK/B, with underscores.
+func fileSafe(symbol string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, symbol)
+}
+
+// runBenchmark prints how summaries compare with holding the benchmark whose
+// closes are in path.
+func runBenchmark(summaries []models.DailySummary, symbol, path string, capital float64, jsonOutput bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	defer f.Close()
+
+	closes, err := stats.LoadBenchmarkCSV(f)
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", path, err)
+	}
+	if symbol == "" {
+		symbol = "Benchmark"
+	}
+
+	b, err := stats.CompareBenchmark(symbol, summaries, closes, capital)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if jsonOutput {
+		writeJSON(b)
+		return
+	}
+	stats.PrintBenchmark(os.Stdout, b)
+}
+
+// runActivity prints how many market days in range had trades, judged by
+// the US calendar or the holidays file at path.
+func runActivity(summaries []models.DailySummary, path, from, to string, jsonOutput bool) {
+	cal := stats.USMarketCalendar()
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		cal, err = stats.LoadHolidays(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("Error reading %s: %v", path, err)
+		}
+	}
+
+	a, err := stats.ComputeActivity(summaries, cal, from, to)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if jsonOutput {
+		writeJSON(a)
+		return
+	}
+	stats.PrintActivity(os.Stdout, a)
+}
+
+// writeJSON writes v to stdout as indented JSON.
+func writeJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Fatalf("Error writing JSON: %v", err)
+	}
+}
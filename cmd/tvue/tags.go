@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+func runTags(args []string) {
+	fs := flag.NewFlagSet("tags", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	fromDate := fs.String("from", "", "Start date filter (yyyy-mm-dd)")
+	toDate := fs.String("to", "", "End date filter (yyyy-mm-dd)")
+	csvOutput := fs.Bool("csv", false, "Output as CSV")
+	outputFile := fs.String("output", "", "Output file (default: stdout)")
+	scratch := fs.Float64("scratch-threshold", 0, "Count trades with |P&L| up to this amount as scratches")
+	winBasis := fs.String("win-basis", "gross", "P&L that decides winners and losers: gross, or net of commission and fees")
+	var symbols stringList
+	fs.Var(&symbols, "symbol", "Only include this symbol (repeatable)")
+	aliases := addAliasFlag(fs)
+	annotations := addAnnotationFlags(fs)
+	byFlag := fs.Bool("by-flag", false, "Break down by local flags instead of Tradervue tags (needs --with-annotations)")
+
+	// Short aliases
+	fs.StringVar(outputFile, "o", "", "")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue tags [options]\n\nShows net P&L, trade count, and win rate per tag (or, with --by-flag, per\nlocal flag). A trade with several tags counts toward each of them.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	if *scratch < 0 {
+		log.Fatalf("Error: --scratch-threshold must not be negative")
+	}
+	basis, err := summary.ParseWinBasis(*winBasis)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	aliasMap, err := aliases.aliases()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	gen := summary.NewGenerator(dirs.path())
+
+	opts := summary.Options{
+		FromDate: *fromDate,
+		ToDate:   *toDate,
+		Symbols:  symbols,
+
+		ScratchThreshold: *scratch,
+		WinBasis:         basis,
+		Aliases:          aliasMap,
+	}
+	if err := annotations.apply(dirs.path(), &opts); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if *byFlag && opts.Annotations == nil {
+		log.Fatalf("Error: --by-flag needs --with-annotations")
+	}
+
+	breakdown := gen.TagSummaries
+	if *byFlag {
+		breakdown = gen.FlagSummaries
+	}
+	tags, err := breakdown(opts)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	if len(tags) == 0 {
+		log.Println("No trades found. Run 'tvue export' first.")
+		return
+	}
+
+	// Determine output writer
+	var w *os.File
+	if *outputFile != "" {
+		f, err := os.Create(*outputFile)
+		if err != nil {
+			log.Fatalf("Error creating output file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	} else {
+		w = os.Stdout
+	}
+
+	if *csvOutput {
+		if err := gen.ExportTagsCSV(w, tags); err != nil {
+			log.Fatalf("Error writing CSV: %v", err)
+		}
+	} else {
+		gen.PrintTags(w, tags)
+	}
+}
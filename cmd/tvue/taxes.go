@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+	"github.com/jefrnc/tradervue-utils/pkg/taxes"
+)
+
+func runTaxes(args []string) {
+	fs := flag.NewFlagSet("taxes", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	year := fs.Int("year", time.Now().Year()-1, "Tax year: trades closed during this year")
+	csvOutput := fs.Bool("csv", false, "Output as CSV (same as --format csv)")
+	format := fs.String("format", "", "Output format: table, csv, or json (default: table)")
+	outputFile := fs.String("output", "", "Output file (default: stdout)")
+	currency := fs.String("currency", "", "Only include trades made in this currency (e.g. USD)")
+
+	// Short aliases
+	fs.StringVar(outputFile, "o", "", "")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue taxes [options]\n\nLists the realized gain or loss of each trade closed during a year, in the\ncolumns of IRS Form 8949. Check the result against your broker's 1099-B.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if *csvOutput {
+		if *format != "" && *format != "csv" {
+			log.Fatalf("Error: --csv conflicts with --format %s; use one or the other", *format)
+		}
+		*format = "csv"
+	}
+	switch *format {
+	case "", "table", "csv", "json":
+	default:
+		log.Fatalf("Error: unknown format %q (use table, csv, or json)", *format)
+	}
+
+	gen := summary.NewGenerator(dirs.path())
+
+	// Every trade, not just the year's: positions opened in earlier years
+	// close in this one, and wash sales look 30 days past either end
+	rows, err := gen.Trades(summary.Options{Currency: *currency})
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if len(rows) == 0 {
+		log.Println("No trades found. Run 'tvue export' first.")
+		return
+	}
+
+	lots := taxes.Lots(rows, *year)
+	if len(lots) == 0 {
+		log.Printf("No trades closed in %d.", *year)
+		return
+	}
+
+	// Determine output writer
+	var w *os.File
+	if *outputFile != "" {
+		f, err := os.Create(*outputFile)
+		if err != nil {
+			log.Fatalf("Error creating output file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	} else {
+		w = os.Stdout
+	}
+
+	switch *format {
+	case "csv":
+		if err := taxes.ExportCSV(w, lots); err != nil {
+			log.Fatalf("Error writing CSV: %v", err)
+		}
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(lots); err != nil {
+			log.Fatalf("Error writing JSON: %v", err)
+		}
+	default:
+		taxes.PrintLots(w, lots)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+func runTrades(args []string) {
+	fs := flag.NewFlagSet("trades", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	fromDate := fs.String("from", "", "Start date filter (yyyy-mm-dd)")
+	toDate := fs.String("to", "", "End date filter (yyyy-mm-dd)")
+	csvOutput := fs.Bool("csv", false, "Output as CSV (same as --format csv)")
+	format := fs.String("format", "", "Output format: table, csv, or jsonl (default: table)")
+	executions := fs.Bool("executions", false, "With --format jsonl, write executions instead of trades")
+	limit := fs.Int("limit", 0, "Show only the N most recent trades (newest first)")
+	after := fs.Int("after", 0, "List trades older than this trade ID (newest first); use the last ID of one page to get the next")
+	outputFile := fs.String("output", "", "Output file (default: stdout)")
+	var symbols stringList
+	fs.Var(&symbols, "symbol", "Only include this symbol (repeatable)")
+	var excludeSymbols stringList
+	fs.Var(&excludeSymbols, "exclude-symbol", "Leave out this symbol, even if also given with --symbol (repeatable)")
+	var tags stringList
+	fs.Var(&tags, "tag", "Only include trades with this tag (repeatable)")
+	tagMode := fs.String("tag-mode", "any", "With several --tag flags, match trades with any or all of them")
+
+	// Short aliases
+	fs.StringVar(outputFile, "o", "", "")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue trades [options]\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if *csvOutput {
+		if *format != "" && *format != "csv" {
+			log.Fatalf("Error: --csv conflicts with --format %s; use one or the other", *format)
+		}
+		*format = "csv"
+	}
+	switch *format {
+	case "", "table", "csv", "jsonl":
+	default:
+		log.Fatalf("Error: unknown format %q (use table, csv, or jsonl)", *format)
+	}
+	if *tagMode != "any" && *tagMode != "all" {
+		log.Fatalf("Error: unknown --tag-mode %q (use any or all)", *tagMode)
+	}
+	if *limit < 0 {
+		log.Fatalf("Error: --limit must not be negative")
+	}
+	if *executions && *format != "jsonl" {
+		log.Fatalf("Error: --executions requires --format jsonl")
+	}
+
+	gen := summary.NewGenerator(dirs.path())
+
+	rows, err := gen.Trades(summary.Options{
+		FromDate: *fromDate,
+		ToDate:   *toDate,
+		Symbols:  symbols,
+
+		ExcludeSymbols: excludeSymbols,
+		Tags:           tags,
+		MatchAllTags:   *tagMode == "all",
+	})
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	if *limit > 0 || *after != 0 {
+		rows, err = summary.Recent(rows, *after, *limit)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if len(rows) == 0 {
+			log.Println("No more trades.")
+			return
+		}
+	}
+
+	if len(rows) == 0 {
+		log.Println("No trades found. Run 'tvue export' first.")
+		return
+	}
+
+	// Determine output writer
+	var w *os.File
+	if *outputFile != "" {
+		f, err := os.Create(*outputFile)
+		if err != nil {
+			log.Fatalf("Error creating output file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	} else {
+		w = os.Stdout
+	}
+
+	switch {
+	case *format == "csv":
+		if err := gen.ExportTradesCSV(w, rows); err != nil {
+			log.Fatalf("Error writing CSV: %v", err)
+		}
+	case *executions:
+		if err := gen.ExportExecutionsJSONL(w, rows); err != nil {
+			log.Fatalf("Error writing JSONL: %v", err)
+		}
+	case *format == "jsonl":
+		if err := gen.ExportTradesJSONL(w, rows); err != nil {
+			log.Fatalf("Error writing JSONL: %v", err)
+		}
+	default:
+		gen.PrintTrades(w, rows)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jefrnc/tradervue-utils/internal/tui"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+func runTUI(args []string) {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+
+	dirs := addDataDirFlags(fs)
+	fromDate := fs.String("from", "", "Start date filter (yyyy-mm-dd)")
+	toDate := fs.String("to", "", "End date filter (yyyy-mm-dd)")
+	aliases := addAliasFlag(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: tvue tui [options]\n\nBrowse daily summaries and trades in an interactive terminal dashboard.\nReads the exported data only; nothing is sent to Tradervue.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	aliasMap, err := aliases.aliases()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	gen := summary.NewGenerator(dirs.path())
+	rows, err := gen.Trades(summary.Options{
+		FromDate: *fromDate,
+		ToDate:   *toDate,
+		Aliases:  aliasMap,
+	})
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if len(rows) == 0 {
+		log.Println("No exported data found. Run 'tvue export' first.")
+		return
+	}
+
+	if err := tui.Run(rows); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
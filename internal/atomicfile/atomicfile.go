@@ -0,0 +1,48 @@
+// Package atomicfile replaces files in a data directory without readers
+// ever seeing them half written.
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// rename moves the finished temp file over the target; tests replace it to
+// interrupt a write at its last step.
+var rename = os.Rename
+
+// Write writes data to a temp file in the same directory and renames it
+// over path, so readers never see a partially written file. On failure the
+// temp file is removed and any previous file at path is left as it was.
+func Write(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package atomicfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "2025-01-02.json")
+
+	if err := Write(path, []byte("old"), 0644); err != nil {
+		t.Fatalf("first write: %v", err)
+	}
+	if err := Write(path, []byte("new"), 0644); err != nil {
+		t.Fatalf("second write: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "new" {
+		t.Errorf("after rewrite, file = %q, want %q", got, "new")
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("file mode = %v (%v), want 0644", info.Mode().Perm(), err)
+	}
+	assertNoTemp(t, dir)
+}
+
+func TestWriteInterrupted(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "2025-01-02.json")
+	if err := os.WriteFile(path, []byte("previous"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fail at the rename, after the new data is fully on disk in the temp file
+	interrupted := errors.New("interrupted")
+	defer func(orig func(string, string) error) { rename = orig }(rename)
+	rename = func(string, string) error { return interrupted }
+
+	if err := Write(path, []byte("replacement"), 0644); !errors.Is(err, interrupted) {
+		t.Fatalf("Write = %v, want %v", err, interrupted)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "previous" {
+		t.Errorf("file = %q after an interrupted write, want the previous %q", got, "previous")
+	}
+	assertNoTemp(t, dir)
+}
+
+// assertNoTemp fails if a write left a temp file behind in dir.
+func assertNoTemp(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("temp file %s left behind", e.Name())
+		}
+	}
+}
@@ -1,8 +1,16 @@
 package config
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/joho/godotenv"
 )
@@ -11,41 +19,351 @@ import (
 type Config struct {
 	Username  string
 	Password  string
+	Token     string
 	DataDir   string
+	UserAgent string // sent with every API request; see DefaultUserAgent
+
+	// RequestDelay overrides the minimum spacing between API requests
+	// (TVUE_REQUEST_DELAY, e.g. "500ms"). Zero means the client default.
+	RequestDelay time.Duration
+
+	// MaxRetries overrides how many attempts each API request gets
+	// (TVUE_MAX_RETRIES). Zero means the client default.
+	MaxRetries int
+
+	// MaxFailures overrides how many failed attempts in a row, across
+	// requests, stop the client as the API being down
+	// (TVUE_MAX_FAILURES). Zero means the client default.
+	MaxFailures int
+
+	// RetryBase and RetryMax override the first retry delay and the cap it
+	// doubles up to (TVUE_RETRY_BASE, TVUE_RETRY_MAX). Zero means the
+	// client defaults.
+	RetryBase time.Duration
+	RetryMax  time.Duration
+
+	// Timeout overrides how long each API request may take
+	// (TVUE_HTTP_TIMEOUT). Zero means the client default.
+	Timeout time.Duration
+
+	// CACert is a PEM bundle of extra root CAs to trust, for networks that
+	// intercept HTTPS (--ca-cert or TVUE_CA_CERT).
+	CACert string
+
+	// Timezone is the IANA zone trades are grouped into days by
+	// (--timezone or TVUE_TIMEZONE). Empty means the exporter default.
+	Timezone string
+
+	// Debug logs every API request and response (--debug).
+	Debug bool
+
+	// LogJSON writes log messages as JSON lines (--log-json).
+	LogJSON bool
+}
+
+// Flags holds CLI flag values that override environment variables.
+// Empty fields leave the environment value in place.
+type Flags struct {
+	Username string
+	Password string
+	Token    string
+	DataDir  string
+	Profile  string // named account profile; empty is the default profile
+	Timezone string
+	CACert   string
+	Debug    bool
+	LogJSON  bool
+
+	// UserAgent replaces DefaultUserAgent; Contact (an email address) is
+	// added to whichever is used.
 	UserAgent string
+	Contact   string
+
+	// PasswordStdin reads the password from the first line of standard
+	// input; PasswordFile reads it from a file only its owner can read.
+	// Either wins over the environment but not over Password.
+	PasswordStdin bool
+	PasswordFile  string
 }
 
+// DefaultDataDir is the data directory when no flag or env var sets one.
+const DefaultDataDir = "./data"
+
+// DefaultUserAgent identifies the client to Tradervue when neither
+// --user-agent nor TVUE_USER_AGENT sets one.
+const DefaultUserAgent = "tvue-cli (https://github.com/jefrnc/tradervue-utils)"
+
 // Load reads configuration from environment variables (and optional .env file).
 // CLI flag values can be passed in to override env vars.
-func Load(flagUsername, flagPassword, flagDataDir string) (*Config, error) {
+func Load(flags Flags) (*Config, error) {
 	// Load .env file if it exists (ignoring errors if missing)
 	_ = godotenv.Load()
 
+	// A named profile reads suffixed variables (TRADERVUE_USERNAME_SWING)
+	// and keeps its data in a subdirectory, unless TVUE_DATA_DIR_SWING is set.
+	profile := flags.Profile
 	cfg := &Config{
-		Username:  envOrDefault("TRADERVUE_USERNAME", ""),
-		Password:  envOrDefault("TRADERVUE_PASSWORD", ""),
-		DataDir:   envOrDefault("TVUE_DATA_DIR", "./data"),
-		UserAgent: "tvue-cli (https://github.com/jefrnc/tradervue-utils)",
+		Username:  envOrDefault(profileKey("TRADERVUE_USERNAME", profile), ""),
+		Password:  envOrDefault(profileKey("TRADERVUE_PASSWORD", profile), ""),
+		Token:     envOrDefault(profileKey("TRADERVUE_API_TOKEN", profile), ""),
+		DataDir:   DataDir(flags.DataDir, profile),
+		Timezone:  os.Getenv("TVUE_TIMEZONE"),
+		CACert:    os.Getenv("TVUE_CA_CERT"),
+		UserAgent: envOrDefault("TVUE_USER_AGENT", DefaultUserAgent),
 	}
 
 	// CLI flags override env vars
-	if flagUsername != "" {
-		cfg.Username = flagUsername
+	if flags.Username != "" {
+		cfg.Username = flags.Username
+	}
+	if flags.PasswordStdin && flags.PasswordFile != "" {
+		return nil, fmt.Errorf("use either --password-stdin or --password-file, not both")
+	}
+	switch {
+	case flags.Password != "":
+		cfg.Password = flags.Password
+	case flags.PasswordStdin:
+		pw, err := readPassword(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading password from stdin: %w", err)
+		}
+		cfg.Password = pw
+	case flags.PasswordFile != "":
+		pw, err := readPasswordFile(ExpandPath(flags.PasswordFile))
+		if err != nil {
+			return nil, err
+		}
+		cfg.Password = pw
+	}
+	if flags.Token != "" {
+		cfg.Token = flags.Token
 	}
-	if flagPassword != "" {
-		cfg.Password = flagPassword
+	if flags.Timezone != "" {
+		cfg.Timezone = flags.Timezone
 	}
-	if flagDataDir != "" {
-		cfg.DataDir = flagDataDir
+	if flags.CACert != "" {
+		cfg.CACert = flags.CACert
+	}
+	if flags.UserAgent != "" {
+		cfg.UserAgent = flags.UserAgent
+	}
+	contact := envOrDefault("TVUE_CONTACT", "")
+	if flags.Contact != "" {
+		contact = flags.Contact
+	}
+	ua, err := userAgent(cfg.UserAgent, contact)
+	if err != nil {
+		return nil, err
+	}
+	cfg.UserAgent = ua
+	cfg.CACert = ExpandPath(cfg.CACert)
+	cfg.Debug = flags.Debug
+	cfg.LogJSON = flags.LogJSON
+
+	if v := os.Getenv("TVUE_REQUEST_DELAY"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid TVUE_REQUEST_DELAY %q (use a duration like 500ms or 1s)", v)
+		}
+		cfg.RequestDelay = d
+	}
+
+	if v := os.Getenv("TVUE_MAX_RETRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid TVUE_MAX_RETRIES %q (use a positive number)", v)
+		}
+		cfg.MaxRetries = n
+	}
+
+	if v := os.Getenv("TVUE_MAX_FAILURES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid TVUE_MAX_FAILURES %q (use a positive number)", v)
+		}
+		cfg.MaxFailures = n
 	}
 
-	if cfg.Username == "" || cfg.Password == "" {
-		return nil, fmt.Errorf("credentials required: set --username/--password flags or TRADERVUE_USERNAME/TRADERVUE_PASSWORD in .env")
+	for _, d := range []struct {
+		key string
+		dst *time.Duration
+	}{
+		{"TVUE_RETRY_BASE", &cfg.RetryBase},
+		{"TVUE_RETRY_MAX", &cfg.RetryMax},
+		{"TVUE_HTTP_TIMEOUT", &cfg.Timeout},
+	} {
+		if v := os.Getenv(d.key); v != "" {
+			dur, err := time.ParseDuration(v)
+			if err != nil || dur <= 0 {
+				return nil, fmt.Errorf("invalid %s %q (use a duration like 30s)", d.key, v)
+			}
+			*d.dst = dur
+		}
+	}
+
+	if cfg.Token == "" && (cfg.Username == "" || cfg.Password == "") {
+		if profile != "" {
+			return nil, fmt.Errorf("credentials required for profile %q: set %s, or %s/%s in .env",
+				profile, profileKey("TRADERVUE_API_TOKEN", profile),
+				profileKey("TRADERVUE_USERNAME", profile), profileKey("TRADERVUE_PASSWORD", profile))
+		}
+		return nil, fmt.Errorf("credentials required: set --token or TRADERVUE_API_TOKEN, or --username/--password flags or TRADERVUE_USERNAME/TRADERVUE_PASSWORD in .env")
 	}
 
 	return cfg, nil
 }
 
+// UsesToken reports whether API token authentication is configured.
+// A token always takes precedence over username and password.
+func (c *Config) UsesToken() bool {
+	return c.Token != ""
+}
+
+// userAgent adds a contact email address to ua: inside its trailing
+// comment when it ends with one, as the default does, or as a new comment.
+func userAgent(ua, contact string) (string, error) {
+	ua = strings.TrimSpace(ua)
+	contact = strings.TrimSpace(contact)
+	if ua == "" {
+		return "", fmt.Errorf("the user agent must not be blank")
+	}
+	if strings.ContainsFunc(ua, unicode.IsControl) {
+		return "", fmt.Errorf("invalid user agent %q: control characters aren't allowed", ua)
+	}
+	if contact == "" {
+		return ua, nil
+	}
+	if strings.Count(contact, "@") != 1 || strings.ContainsFunc(contact, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r) || strings.ContainsRune("()<>;", r)
+	}) {
+		return "", fmt.Errorf("invalid contact %q (use an email address, e.g. you@example.com)", contact)
+	}
+	if strings.HasSuffix(ua, ")") {
+		return ua[:len(ua)-1] + "; " + contact + ")", nil
+	}
+	return ua + " (" + contact + ")", nil
+}
+
+// readPassword returns the first line of r, without its line ending.
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	pw := strings.TrimRight(line, "\r\n")
+	if pw == "" {
+		return "", fmt.Errorf("no password given")
+	}
+	return pw, nil
+}
+
+// readPasswordFile reads the password from path, refusing a file that
+// other users can read.
+func readPasswordFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening password file: %w", err)
+	}
+	defer f.Close()
+
+	// Windows has no Unix permission bits to check
+	if runtime.GOOS != "windows" {
+		info, err := f.Stat()
+		if err != nil {
+			return "", fmt.Errorf("opening password file: %w", err)
+		}
+		if mode := info.Mode().Perm(); mode&0077 != 0 {
+			return "", fmt.Errorf("password file %s is readable by other users (mode %04o); run chmod 600 %s", path, mode, path)
+		}
+	}
+
+	pw, err := readPassword(f)
+	if err != nil {
+		return "", fmt.Errorf("reading password file %s: %w", path, err)
+	}
+	return pw, nil
+}
+
+// ConfiguredTimezone returns TVUE_TIMEZONE, reading .env as Load does, for
+// commands that don't load credentials. Empty means the exporter default.
+func ConfiguredTimezone() string {
+	_ = godotenv.Load()
+	return os.Getenv("TVUE_TIMEZONE")
+}
+
+// DataDir returns the data directory for a --data-dir value (empty when
+// not given) and profile, reading .env as Load does: the flag, else
+// TVUE_DATA_DIR_<PROFILE>, else the profile's subdirectory of
+// TVUE_DATA_DIR (default ./data). ~ and environment variables in it are
+// expanded.
+func DataDir(flagDir, profile string) string {
+	_ = godotenv.Load()
+	dir := flagDir
+	if dir == "" {
+		dir = envOrDefault(profileKey("TVUE_DATA_DIR", profile), "")
+	}
+	if dir == "" {
+		dir = ProfileDataDir(envOrDefault("TVUE_DATA_DIR", DefaultDataDir), profile)
+	}
+	return ExpandPath(dir)
+}
+
+// ConfiguredAliases returns the OLD=NEW symbol aliases in
+// TVUE_SYMBOL_ALIASES (from the environment or .env), comma-separated.
+func ConfiguredAliases() []string {
+	_ = godotenv.Load()
+	var pairs []string
+	for _, p := range strings.Split(os.Getenv("TVUE_SYMBOL_ALIASES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			pairs = append(pairs, p)
+		}
+	}
+	return pairs
+}
+
+// ExpandPath expands environment variables ($VAR or ${VAR}) in path, and a
+// leading ~ to the home directory, so values such as TVUE_DATA_DIR=~/trades
+// work even where no shell has expanded them. The ~ is left as is when the
+// home directory is unknown.
+func ExpandPath(path string) string {
+	path = os.ExpandEnv(path)
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// ProfileDataDir returns the data directory for a profile under base.
+// The default (empty) profile uses base itself.
+func ProfileDataDir(base, profile string) string {
+	if profile == "" {
+		return base
+	}
+	return filepath.Join(base, profile)
+}
+
+// profileKey appends the profile suffix to an env var name, e.g.
+// TRADERVUE_USERNAME + "swing" -> TRADERVUE_USERNAME_SWING.
+func profileKey(key, profile string) string {
+	if profile == "" {
+		return key
+	}
+	suffix := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, profile)
+	return key + "_" + suffix
+}
+
 func envOrDefault(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("TVUE_TEST_ROOT", "/srv/trading")
+	t.Setenv("TVUE_TEST_EMPTY", "")
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"./data", "./data"},
+		{"/var/lib/tvue", "/var/lib/tvue"},
+		{"~", home},
+		{"~/tradervue", filepath.Join(home, "tradervue")},
+		{"~/a/../b", filepath.Join(home, "b")},
+		{"~other/data", "~other/data"}, // another user's home is not expanded
+		{"data/~", "data/~"},
+		{"$TVUE_TEST_ROOT/data", "/srv/trading/data"},
+		{"${TVUE_TEST_ROOT}/data", "/srv/trading/data"},
+		{"${TVUE_TEST_ROOT}data", "/srv/tradingdata"},
+		{"$HOME/tradervue", filepath.Join(home, "tradervue")},
+		{"$TVUE_TEST_UNSET/data", "/data"},
+		{"${TVUE_TEST_EMPTY}data", "data"},
+	}
+	for _, tt := range tests {
+		if got := ExpandPath(tt.in); got != tt.want {
+			t.Errorf("ExpandPath(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDataDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		flag    string
+		profile string
+		want    string
+	}{
+		{name: "default", want: "data"},
+		{name: "default profile", profile: "swing", want: "data/swing"},
+		{name: "TVUE_DATA_DIR", env: map[string]string{"TVUE_DATA_DIR": "/srv/tv"}, want: "/srv/tv"},
+		{name: "TVUE_DATA_DIR with profile", env: map[string]string{"TVUE_DATA_DIR": "/srv/tv"}, profile: "swing", want: "/srv/tv/swing"},
+		{name: "profile's own dir", env: map[string]string{"TVUE_DATA_DIR": "/srv/tv", "TVUE_DATA_DIR_SWING": "~/swing"}, profile: "swing", want: filepath.Join(home, "swing")},
+		{name: "profile dir needs the profile", env: map[string]string{"TVUE_DATA_DIR_SWING": "/srv/swing"}, want: "data"},
+		{name: "flag wins", env: map[string]string{"TVUE_DATA_DIR": "/srv/tv", "TVUE_DATA_DIR_SWING": "/srv/swing"}, flag: "$HOME/mine", profile: "swing", want: filepath.Join(home, "mine")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TVUE_DATA_DIR", "")
+			t.Setenv("TVUE_DATA_DIR_SWING", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if got := filepath.Clean(DataDir(tt.flag, tt.profile)); got != filepath.Clean(tt.want) {
+				t.Errorf("DataDir(%q, %q) = %q, want %q", tt.flag, tt.profile, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Package dayfile locates, reads, and encodes the per-day export files in
+// a data directory. A day file is trades/yyyy-mm-dd.json, or
+// trades/yyyy-mm-dd.json.gz when written with compression; readers handle
+// both transparently, choosing by extension.
+package dayfile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+const (
+	// Dir is the data subdirectory holding day files.
+	Dir = "trades"
+
+	jsonExt = ".json"
+	gzipExt = ".json.gz"
+)
+
+// File is a day file on disk.
+type File struct {
+	Date       string // yyyy-mm-dd, from the filename
+	Path       string
+	Compressed bool
+	ModTime    time.Time
+
+	// Shadowed lists older files for the same date that List passed over,
+	// such as a .json left beside a newer .json.gz.
+	Shadowed []string
+}
+
+// Name returns the filename for date's day file.
+func Name(date string, compressed bool) string {
+	if compressed {
+		return date + gzipExt
+	}
+	return date + jsonExt
+}
+
+// Path returns where date's day file is written in dataDir.
+func Path(dataDir, date string, compressed bool) string {
+	return filepath.Join(dataDir, Dir, Name(date, compressed))
+}
+
+// parseName returns the date and compression of a day file name.
+func parseName(name string) (string, bool, bool) {
+	switch {
+	case strings.HasSuffix(name, gzipExt):
+		return strings.TrimSuffix(name, gzipExt), true, true
+	case strings.HasSuffix(name, jsonExt):
+		return strings.TrimSuffix(name, jsonExt), false, true
+	}
+	return "", false, false
+}
+
+// List returns the day files in dataDir sorted by date. If a day has both
+// forms (say, after an interrupted re-export), the newer file is used, or
+// the .json when neither is newer, and the other is recorded in its
+// Shadowed. Files whose name isn't a
+// yyyy-mm-dd date, such as a copy saved as "2025-01-15 (1).json", are not
+// day files and are left out.
+func List(dataDir string) ([]File, error) {
+	dir := filepath.Join(dataDir, Dir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading trades directory: %w", err)
+	}
+
+	byDate := make(map[string]File, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		date, compressed, ok := parseName(entry.Name())
+		if !ok {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		f := File{
+			Date:       date,
+			Path:       filepath.Join(dir, entry.Name()),
+			Compressed: compressed,
+			ModTime:    info.ModTime(),
+		}
+		if prev, ok := byDate[date]; ok {
+			if !f.ModTime.After(prev.ModTime) {
+				prev.Shadowed = append(prev.Shadowed, f.Path)
+				byDate[date] = prev
+				continue
+			}
+			f.Shadowed = append(prev.Shadowed, prev.Path)
+		}
+		byDate[date] = f
+	}
+
+	files := make([]File, 0, len(byDate))
+	for _, f := range byDate {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Date < files[j].Date
+	})
+	return files, nil
+}
+
+// Find returns date's day file in dataDir, in whichever form exists,
+// choosing between both forms as List does.
+func Find(dataDir, date string) (File, error) {
+	var found File
+	ok := false
+	for _, compressed := range []bool{false, true} {
+		path := Path(dataDir, date, compressed)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !ok || info.ModTime().After(found.ModTime) {
+			found = File{Date: date, Path: path, Compressed: compressed, ModTime: info.ModTime()}
+			ok = true
+		}
+	}
+	if !ok {
+		return File{}, fmt.Errorf("no day file for %s: %w", date, os.ErrNotExist)
+	}
+	return found, nil
+}
+
+// ReadRaw returns a day file's bytes as stored on disk.
+func ReadRaw(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// Decode parses a day file's stored bytes, decompressing when path ends
+// in .gz.
+func Decode(path string, raw []byte) (*models.DayExport, error) {
+	data := raw
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("decompressing: %w", err)
+		}
+		data, err = io.ReadAll(zr)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing: %w", err)
+		}
+	}
+
+	var day models.DayExport
+	if err := json.Unmarshal(data, &day); err != nil {
+		return nil, err
+	}
+	return &day, nil
+}
+
+// Load reads and parses a day file.
+func Load(path string) (*models.DayExport, error) {
+	raw, err := ReadRaw(path)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(path, raw)
+}
+
+// Encode returns the bytes to store for day: indented JSON, gzipped when
+// compressed is set.
+func Encode(day *models.DayExport, compressed bool) ([]byte, error) {
+	data, err := json.MarshalIndent(day, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if !compressed {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RemoveOther deletes date's day file in the form other than compressed,
+// so a re-export in one form replaces a file in the other.
+func RemoveOther(dataDir, date string, compressed bool) error {
+	err := os.Remove(Path(dataDir, date, !compressed))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package dayfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+func TestListSameDateBothForms(t *testing.T) {
+	const date = "2025-01-02"
+	base := time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		jsonAge        time.Duration // how long before base each file was written
+		gzipAge        time.Duration
+		wantCompressed bool
+	}{
+		{name: "gzip newer", jsonAge: time.Hour, gzipAge: 0, wantCompressed: true},
+		{name: "json newer", jsonAge: 0, gzipAge: time.Hour, wantCompressed: false},
+		// Neither is newer: the plain file, listed first, is kept
+		{name: "same time", jsonAge: 0, gzipAge: 0, wantCompressed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeDay(t, dir, date, false, 1, base.Add(-tt.jsonAge))
+			writeDay(t, dir, date, true, 2, base.Add(-tt.gzipAge))
+			// Copies that aren't named for a date are never day files
+			if err := os.WriteFile(filepath.Join(dir, Dir, date+" (1).json"), []byte("{}"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			files, err := List(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != 1 {
+				t.Fatalf("List returned %d files, want 1: %+v", len(files), files)
+			}
+			f := files[0]
+			if f.Date != date || f.Compressed != tt.wantCompressed {
+				t.Errorf("List chose %s, want compressed=%v", filepath.Base(f.Path), tt.wantCompressed)
+			}
+			if want := Path(dir, date, !tt.wantCompressed); len(f.Shadowed) != 1 || f.Shadowed[0] != want {
+				t.Errorf("Shadowed = %v, want [%s]", f.Shadowed, want)
+			}
+
+			found, err := Find(dir, date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found.Path != f.Path {
+				t.Errorf("Find chose %s but List chose %s", filepath.Base(found.Path), filepath.Base(f.Path))
+			}
+
+			day, err := Load(f.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantID := 1
+			if tt.wantCompressed {
+				wantID = 2
+			}
+			if len(day.Trades) != 1 || day.Trades[0].ID != wantID {
+				t.Errorf("loaded trades %+v, want the one trade of the chosen file (ID %d)", day.Trades, wantID)
+			}
+		})
+	}
+}
+
+// writeDay writes a one-trade day file for date with the given mod time.
+func writeDay(t *testing.T, dataDir, date string, compressed bool, tradeID int, modTime time.Time) {
+	t.Helper()
+	data, err := Encode(&models.DayExport{Date: date, Trades: []models.Trade{{ID: tradeID, Symbol: "AAPL"}}}, compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := Path(dataDir, date, compressed)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
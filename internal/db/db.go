@@ -0,0 +1,287 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+
+	_ "modernc.org/sqlite" // pure-Go driver, keeps cross-compilation CGO-free
+)
+
+// schema creates the tables and indexes. Every statement is idempotent so it
+// can run on each open.
+const schema = `
+CREATE TABLE IF NOT EXISTS trades (
+	id                    INTEGER PRIMARY KEY,
+	date                  TEXT NOT NULL,
+	symbol                TEXT NOT NULL,
+	side                  TEXT NOT NULL,
+	volume                INTEGER NOT NULL,
+	open                  INTEGER NOT NULL,
+	entry_price           REAL NOT NULL,
+	exit_price            REAL,
+	gross_pl              REAL NOT NULL,
+	native_pl             REAL,
+	native_currency       TEXT,
+	commission            REAL NOT NULL,
+	fees                  REAL NOT NULL,
+	start_datetime        TEXT NOT NULL,
+	end_datetime          TEXT,
+	duration              TEXT NOT NULL,
+	notes                 TEXT NOT NULL,
+	notes_excerpt         TEXT NOT NULL,
+	shared                INTEGER NOT NULL,
+	initial_risk          REAL,
+	exec_count            INTEGER NOT NULL,
+	comment_count         INTEGER NOT NULL,
+	position_mfe          REAL,
+	position_mfe_datetime TEXT,
+	position_mae          REAL,
+	position_mae_datetime TEXT,
+	price_mfe             REAL,
+	price_mfe_datetime    TEXT,
+	price_mae             REAL,
+	price_mae_datetime    TEXT,
+	best_exit_pl          REAL,
+	best_exit_pl_datetime TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_trades_symbol ON trades(symbol);
+CREATE INDEX IF NOT EXISTS idx_trades_start_datetime ON trades(start_datetime);
+
+CREATE TABLE IF NOT EXISTS trade_tags (
+	trade_id INTEGER NOT NULL REFERENCES trades(id) ON DELETE CASCADE,
+	tag      TEXT NOT NULL,
+	PRIMARY KEY (trade_id, tag)
+);
+CREATE INDEX IF NOT EXISTS idx_trade_tags_tag ON trade_tags(tag);
+
+CREATE TABLE IF NOT EXISTS executions (
+	id         INTEGER PRIMARY KEY,
+	trade_id   INTEGER NOT NULL REFERENCES trades(id) ON DELETE CASCADE,
+	datetime   TEXT NOT NULL,
+	symbol     TEXT NOT NULL,
+	quantity   INTEGER NOT NULL,
+	price      REAL NOT NULL,
+	commission REAL NOT NULL,
+	trans_fee  REAL NOT NULL,
+	ecn_fee    REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_executions_trade_id ON executions(trade_id);
+
+CREATE TABLE IF NOT EXISTS journal (
+	id            INTEGER PRIMARY KEY,
+	date          TEXT NOT NULL,
+	notes         TEXT NOT NULL,
+	comment_count INTEGER NOT NULL,
+	trade_count   INTEGER NOT NULL,
+	total_volume  INTEGER NOT NULL,
+	gross_pl      REAL NOT NULL,
+	commfees      REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_journal_date ON journal(date);
+`
+
+const upsertTrade = `
+INSERT INTO trades (
+	id, date, symbol, side, volume, open, entry_price, exit_price, gross_pl,
+	native_pl, native_currency, commission, fees, start_datetime, end_datetime,
+	duration, notes, notes_excerpt, shared, initial_risk, exec_count, comment_count,
+	position_mfe, position_mfe_datetime, position_mae, position_mae_datetime,
+	price_mfe, price_mfe_datetime, price_mae, price_mae_datetime,
+	best_exit_pl, best_exit_pl_datetime
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+	date = excluded.date,
+	symbol = excluded.symbol,
+	side = excluded.side,
+	volume = excluded.volume,
+	open = excluded.open,
+	entry_price = excluded.entry_price,
+	exit_price = excluded.exit_price,
+	gross_pl = excluded.gross_pl,
+	native_pl = excluded.native_pl,
+	native_currency = excluded.native_currency,
+	commission = excluded.commission,
+	fees = excluded.fees,
+	start_datetime = excluded.start_datetime,
+	end_datetime = excluded.end_datetime,
+	duration = excluded.duration,
+	notes = excluded.notes,
+	notes_excerpt = excluded.notes_excerpt,
+	shared = excluded.shared,
+	initial_risk = excluded.initial_risk,
+	exec_count = excluded.exec_count,
+	comment_count = excluded.comment_count,
+	position_mfe = excluded.position_mfe,
+	position_mfe_datetime = excluded.position_mfe_datetime,
+	position_mae = excluded.position_mae,
+	position_mae_datetime = excluded.position_mae_datetime,
+	price_mfe = excluded.price_mfe,
+	price_mfe_datetime = excluded.price_mfe_datetime,
+	price_mae = excluded.price_mae,
+	price_mae_datetime = excluded.price_mae_datetime,
+	best_exit_pl = excluded.best_exit_pl,
+	best_exit_pl_datetime = excluded.best_exit_pl_datetime`
+
+const upsertExecution = `
+INSERT INTO executions (id, trade_id, datetime, symbol, quantity, price, commission, trans_fee, ecn_fee)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+	trade_id = excluded.trade_id,
+	datetime = excluded.datetime,
+	symbol = excluded.symbol,
+	quantity = excluded.quantity,
+	price = excluded.price,
+	commission = excluded.commission,
+	trans_fee = excluded.trans_fee,
+	ecn_fee = excluded.ecn_fee`
+
+const upsertJournal = `
+INSERT INTO journal (id, date, notes, comment_count, trade_count, total_volume, gross_pl, commfees)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+	date = excluded.date,
+	notes = excluded.notes,
+	comment_count = excluded.comment_count,
+	trade_count = excluded.trade_count,
+	total_volume = excluded.total_volume,
+	gross_pl = excluded.gross_pl,
+	commfees = excluded.commfees`
+
+// ImportStats reports what an import wrote.
+type ImportStats struct {
+	Days       int
+	Trades     int
+	Executions int
+	Journal    int
+}
+
+// DB is a SQLite database of exported trades.
+type DB struct {
+	conn *sql.DB
+}
+
+// Open opens (or creates) the database at path and ensures the schema exists.
+func Open(path string) (*DB, error) {
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	if _, err := conn.Exec(schema); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("creating schema: %w", err)
+	}
+
+	return &DB{conn: conn}, nil
+}
+
+// Close closes the database.
+func (d *DB) Close() error {
+	return d.conn.Close()
+}
+
+// ImportDir upserts every day file in dataDir within the date range.
+// Dates are yyyy-mm-dd; empty strings mean no filter. Re-running an import
+// is idempotent since rows are keyed on their Tradervue IDs.
+func (d *DB) ImportDir(dataDir, fromDate, toDate string) (*ImportStats, error) {
+	files, err := dayfile.List(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ImportStats{}
+
+	for _, f := range files {
+		date := f.Date
+		if fromDate != "" && date < fromDate {
+			continue
+		}
+		if toDate != "" && date > toDate {
+			continue
+		}
+
+		day, err := dayfile.Load(f.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", filepath.Base(f.Path), err)
+		}
+
+		if err := d.importDay(date, day, stats); err != nil {
+			return nil, fmt.Errorf("importing %s: %w", date, err)
+		}
+		stats.Days++
+	}
+
+	return stats, nil
+}
+
+// importDay writes one day's trades, tags, executions, and journal entry in
+// a single transaction.
+func (d *DB) importDay(date string, day *models.DayExport, stats *ImportStats) error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, t := range day.Trades {
+		if _, err := tx.Exec(upsertTrade,
+			t.ID, date, t.Symbol, t.Side, t.Volume, t.Open, t.EntryPrice, t.ExitPrice, t.GrossPL,
+			t.NativePL, t.NativeCurrency, t.Commission, t.Fees, t.StartDatetime, t.EndDatetime,
+			t.Duration, t.Notes, t.NotesExcerpt, t.Shared, t.InitialRisk, t.ExecCount, t.CommentCount,
+			t.PositionMFE, t.PositionMFEDatetime, t.PositionMAE, t.PositionMAEDatetime,
+			t.PriceMFE, t.PriceMFEDatetime, t.PriceMAE, t.PriceMAEDatetime,
+			t.BestExitPL, t.BestExitPLDatetime,
+		); err != nil {
+			return fmt.Errorf("trade %d: %w", t.ID, err)
+		}
+
+		// Replace tags wholesale so removed tags don't linger
+		if _, err := tx.Exec(`DELETE FROM trade_tags WHERE trade_id = ?`, t.ID); err != nil {
+			return fmt.Errorf("tags for trade %d: %w", t.ID, err)
+		}
+		for _, tag := range t.Tags {
+			if _, err := tx.Exec(`INSERT OR IGNORE INTO trade_tags (trade_id, tag) VALUES (?, ?)`, t.ID, tag); err != nil {
+				return fmt.Errorf("tags for trade %d: %w", t.ID, err)
+			}
+		}
+		stats.Trades++
+	}
+
+	tradeIDs := make([]int, 0, len(day.Executions))
+	for id := range day.Executions {
+		tradeIDs = append(tradeIDs, id)
+	}
+	sort.Ints(tradeIDs)
+
+	for _, tradeID := range tradeIDs {
+		for i, x := range day.Executions[tradeID] {
+			// Day files synthesized before executions had IDs left them 0,
+			// which would make every synthetic fill the same row
+			if x.Synthetic && x.ID == 0 {
+				x.ID = models.SyntheticExecutionID(tradeID, i)
+			}
+			if _, err := tx.Exec(upsertExecution,
+				x.ID, tradeID, x.Datetime, x.Symbol, x.Quantity, x.Price, x.Commission, x.TransFee, x.ECNFee,
+			); err != nil {
+				return fmt.Errorf("execution %d: %w", x.ID, err)
+			}
+			stats.Executions++
+		}
+	}
+
+	if j := day.Journal; j != nil {
+		if _, err := tx.Exec(upsertJournal,
+			j.ID, date, j.Notes, j.CommentCount, j.TradeCount, j.TotalVolume, j.GrossPL, j.CommFees,
+		); err != nil {
+			return fmt.Errorf("journal %d: %w", j.ID, err)
+		}
+		stats.Journal++
+	}
+
+	return tx.Commit()
+}
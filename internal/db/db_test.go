@@ -0,0 +1,76 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+func TestImportSyntheticExecutions(t *testing.T) {
+	const date = "2025-01-02"
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, dayfile.Dir), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Trade 1's fills carry their synthetic IDs; trade 2's were written
+	// before synthetic fills had IDs and are all 0
+	synth := func(id int, fill int) models.Execution {
+		return models.Execution{ID: id, Symbol: "AAPL", Quantity: 100 - 200*fill, Price: 100, Synthetic: true}
+	}
+	day := &models.DayExport{
+		Date: date,
+		Trades: []models.Trade{
+			{ID: 1, Symbol: "AAPL", Side: "L", Volume: 100},
+			{ID: 2, Symbol: "AAPL", Side: "L", Volume: 100},
+		},
+		Executions: map[int][]models.Execution{
+			1: {synth(models.SyntheticExecutionID(1, 0), 0), synth(models.SyntheticExecutionID(1, 1), 1)},
+			2: {synth(0, 0), synth(0, 1)},
+		},
+	}
+	data, err := dayfile.Encode(day, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dayfile.Path(dir, date, false), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Open(filepath.Join(dir, "trades.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	// A second import must update the same rows, not add more
+	for run := 1; run <= 2; run++ {
+		stats, err := d.ImportDir(dir, "", "")
+		if err != nil {
+			t.Fatalf("import %d: %v", run, err)
+		}
+		if stats.Executions != 4 {
+			t.Errorf("import %d: ImportStats.Executions = %d, want 4", run, stats.Executions)
+		}
+
+		rows, err := d.conn.Query(`SELECT trade_id, COUNT(*) FROM executions GROUP BY trade_id ORDER BY trade_id`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[int]int{}
+		for rows.Next() {
+			var tradeID, n int
+			if err := rows.Scan(&tradeID, &n); err != nil {
+				t.Fatal(err)
+			}
+			got[tradeID] = n
+		}
+		rows.Close()
+		if got[1] != 2 || got[2] != 2 || len(got) != 2 {
+			t.Errorf("import %d: execution rows per trade = %v, want 2 each for trades 1 and 2", run, got)
+		}
+	}
+}
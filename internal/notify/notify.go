@@ -0,0 +1,109 @@
+// Package notify posts a short report to a webhook when an export finishes.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+// Export statuses.
+const (
+	StatusOK      = "ok"      // everything was exported
+	StatusWarning = "warning" // finished, but some days had problems
+	StatusError   = "error"   // the export failed
+)
+
+// timeout bounds each notification, so a dead endpoint can't hold up a run.
+const timeout = 10 * time.Second
+
+// Payload is the JSON body posted to --notify-url.
+type Payload struct {
+	Status   string    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	Dates    []string  `json:"dates"` // yyyy-mm-dd, the day files written
+	Days     int       `json:"days"`
+	Trades   int       `json:"trades"`
+	NetPL    float64   `json:"net_pl"` // over the written days
+	Warnings []string  `json:"warnings,omitempty"`
+	Finished time.Time `json:"finished_at"`
+}
+
+// Post sends p as JSON to url.
+func Post(ctx context.Context, url string, p Payload) error {
+	return post(ctx, url, p)
+}
+
+// PostSlack sends p to a Slack incoming webhook as a formatted message.
+func PostSlack(ctx context.Context, url string, p Payload) error {
+	return post(ctx, url, map[string]string{"text": SlackText(p)})
+}
+
+// SlackText formats p as a Slack message.
+func SlackText(p Payload) string {
+	var b strings.Builder
+	switch p.Status {
+	case StatusError:
+		fmt.Fprintf(&b, ":x: Tradervue export failed: %s", p.Error)
+	case StatusWarning:
+		fmt.Fprintf(&b, ":warning: Tradervue export finished with %d problems", len(p.Warnings))
+	default:
+		b.WriteString(":white_check_mark: Tradervue export complete")
+	}
+
+	if p.Days > 0 {
+		fmt.Fprintf(&b, "\n%d days (%s), %d trades, net P&L %s",
+			p.Days, dateSpan(p.Dates), p.Trades, summary.FormatPL(p.NetPL))
+	} else if p.Status != StatusError {
+		b.WriteString("\nNo new trades.")
+	}
+	for _, w := range p.Warnings {
+		fmt.Fprintf(&b, "\n• %s", w)
+	}
+	return b.String()
+}
+
+func post(ctx context.Context, url string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// dateSpan is "2025-01-15" for one date or "2025-01-15 to 2025-01-17".
+func dateSpan(dates []string) string {
+	if len(dates) == 0 {
+		return ""
+	}
+	first, last := dates[0], dates[len(dates)-1]
+	if first == last {
+		return first
+	}
+	return first + " to " + last
+}
@@ -0,0 +1,422 @@
+// Package tui is the read-only terminal dashboard behind "tvue tui": the
+// daily summary table with an equity sparkline, drill-down into a day's
+// trades, and live symbol and tag filters. Everything is computed from
+// trades loaded once from the day files; it never calls the API.
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Run shows the dashboard for rows, as returned by Generator.Trades, until
+// the user quits.
+func Run(rows []summary.DayTrade) error {
+	_, err := tea.NewProgram(newModel(rows), tea.WithAltScreen()).Run()
+	return err
+}
+
+type view int
+
+const (
+	viewDays   view = iota // daily summary table
+	viewTrades             // one day's trades
+)
+
+type field int
+
+const (
+	fieldNone field = iota
+	fieldSymbol
+	fieldTag
+)
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	headerStyle   = lipgloss.NewStyle().Bold(true).Underline(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	helpStyle     = lipgloss.NewStyle().Faint(true)
+	activeStyle   = lipgloss.NewStyle().Bold(true).Underline(true)
+	greenStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	redStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// chromeLines is how many lines the title, sparkline, filters, table
+// header, and help take, leaving the rest of the screen for rows.
+const chromeLines = 7
+
+type model struct {
+	all []summary.DayTrade
+
+	// Filtered view of all, recomputed whenever a filter changes
+	days   []models.DailySummary
+	byDate map[string][]summary.DayTrade
+
+	view     view
+	editing  field
+	symbol   string // symbols starting with this, case-insensitive
+	tag      string // trades with a tag containing this, case-insensitive
+	cursor   int    // selected day
+	offset   int    // first day shown
+	tradeSel int    // selected trade in viewTrades
+	tradeOff int
+	width    int
+	height   int
+}
+
+func newModel(rows []summary.DayTrade) *model {
+	m := &model{all: rows, width: 80, height: 24}
+	m.recompute()
+	return m
+}
+
+func (m *model) Init() tea.Cmd { return nil }
+
+// recompute applies the filters and rebuilds the daily summaries.
+func (m *model) recompute() {
+	symbol := strings.ToUpper(m.symbol)
+	tag := strings.ToLower(m.tag)
+
+	m.byDate = make(map[string][]summary.DayTrade)
+	var dates []string
+	for _, r := range m.all {
+		if !strings.HasPrefix(strings.ToUpper(r.Trade.Symbol), symbol) || !hasTag(r.Trade, tag) {
+			continue
+		}
+		if _, ok := m.byDate[r.Date]; !ok {
+			dates = append(dates, r.Date)
+		}
+		m.byDate[r.Date] = append(m.byDate[r.Date], r)
+	}
+
+	m.days = m.days[:0]
+	for _, date := range dates {
+		trades := make([]models.Trade, len(m.byDate[date]))
+		for i, r := range m.byDate[date] {
+			trades[i] = r.Trade
+		}
+		m.days = append(m.days, summary.Summarize(date, trades))
+	}
+
+	m.cursor = min(m.cursor, max(len(m.days)-1, 0))
+	m.scroll()
+}
+
+func hasTag(t models.Trade, tag string) bool {
+	if tag == "" {
+		return true
+	}
+	for _, tg := range t.Tags {
+		if strings.Contains(strings.ToLower(tg), tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// visible is how many table rows fit on screen.
+func (m *model) visible() int {
+	return max(m.height-chromeLines, 1)
+}
+
+// scroll keeps the selected row on screen.
+func (m *model) scroll() {
+	n := m.visible()
+	if m.view == viewDays {
+		m.offset = clampOffset(m.cursor, m.offset, n)
+	} else {
+		m.tradeOff = clampOffset(m.tradeSel, m.tradeOff, n)
+	}
+}
+
+func clampOffset(sel, off, n int) int {
+	if sel < off {
+		return sel
+	}
+	if sel >= off+n {
+		return sel - n + 1
+	}
+	return off
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scroll()
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.editing != fieldNone {
+			m.editFilter(msg)
+			return m, nil
+		}
+		if m.view == viewTrades {
+			return m, m.updateTrades(msg)
+		}
+		return m, m.updateDays(msg)
+	}
+	return m, nil
+}
+
+// editFilter types into the filter being edited, refiltering on every key.
+func (m *model) editFilter(msg tea.KeyMsg) {
+	f := &m.symbol
+	if m.editing == fieldTag {
+		f = &m.tag
+	}
+	switch msg.Type {
+	case tea.KeyEnter, tea.KeyEsc, tea.KeyTab:
+		m.editing = fieldNone
+		return
+	case tea.KeyBackspace:
+		if r := []rune(*f); len(r) > 0 {
+			*f = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		*f += string(msg.Runes)
+	default:
+		return
+	}
+	m.recompute()
+}
+
+func (m *model) updateDays(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q":
+		return tea.Quit
+	case "up", "k":
+		m.cursor = max(m.cursor-1, 0)
+	case "down", "j":
+		m.cursor = min(m.cursor+1, max(len(m.days)-1, 0))
+	case "pgup":
+		m.cursor = max(m.cursor-m.visible(), 0)
+	case "pgdown":
+		m.cursor = min(m.cursor+m.visible(), max(len(m.days)-1, 0))
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = max(len(m.days)-1, 0)
+	case "enter", "right", "l":
+		if len(m.days) > 0 {
+			m.view = viewTrades
+			m.tradeSel, m.tradeOff = 0, 0
+		}
+	case "/", "s":
+		m.editing = fieldSymbol
+	case "t":
+		m.editing = fieldTag
+	case "esc":
+		m.symbol, m.tag = "", ""
+		m.recompute()
+	}
+	m.scroll()
+	return nil
+}
+
+func (m *model) updateTrades(msg tea.KeyMsg) tea.Cmd {
+	n := len(m.byDate[m.days[m.cursor].Date])
+	switch msg.String() {
+	case "q":
+		return tea.Quit
+	case "esc", "backspace", "left", "h":
+		m.view = viewDays
+	case "up", "k":
+		m.tradeSel = max(m.tradeSel-1, 0)
+	case "down", "j":
+		m.tradeSel = min(m.tradeSel+1, max(n-1, 0))
+	case "home", "g":
+		m.tradeSel = 0
+	case "end", "G":
+		m.tradeSel = max(n-1, 0)
+	}
+	m.scroll()
+	return nil
+}
+
+func (m *model) View() string {
+	var b strings.Builder
+
+	var net float64
+	for _, d := range m.days {
+		net += d.NetPL
+	}
+	days := "days"
+	if len(m.days) == 1 {
+		days = "day"
+	}
+	fmt.Fprintf(&b, "%s  %d %s  net %s\n", titleStyle.Render("tvue"), len(m.days), days, colorPL(net))
+	b.WriteString(m.sparkline() + "\n")
+	b.WriteString(m.filters() + "\n\n")
+
+	if m.view == viewTrades {
+		m.viewTrades(&b)
+		b.WriteString(helpStyle.Render("↑/↓ move  esc back  q quit"))
+		return b.String()
+	}
+
+	m.viewDays(&b)
+	b.WriteString(helpStyle.Render("↑/↓ move  enter trades  / symbol  t tag  esc clear filters  q quit"))
+	return b.String()
+}
+
+func (m *model) filters() string {
+	label := func(name, value string, f field) string {
+		s := fmt.Sprintf("%s: %s", name, value)
+		if m.editing == f {
+			return activeStyle.Render(s + "_")
+		}
+		if value == "" {
+			return helpStyle.Render(name + ": any")
+		}
+		return s
+	}
+	return label("symbol", m.symbol, fieldSymbol) + "   " + label("tag", m.tag, fieldTag)
+}
+
+func (m *model) viewDays(b *strings.Builder) {
+	b.WriteString(headerStyle.Render(fmt.Sprintf("%-11s %6s %12s %5s  %s", "DATE", "TRADES", "NET P&L", "WIN%", "SYMBOLS")) + "\n")
+	if len(m.days) == 0 {
+		b.WriteString("No trades match the filters.\n")
+	}
+
+	end := min(m.offset+m.visible(), len(m.days))
+	for i := m.offset; i < end; i++ {
+		d := m.days[i]
+		var syms []string
+		for _, s := range d.Symbols {
+			syms = append(syms, s.Symbol)
+		}
+		line := fmt.Sprintf("%-11s %6d %12s %4.0f%%  %s", d.Date, d.TradeCount, summary.FormatPL(d.NetPL), d.WinRate, strings.Join(syms, " "))
+		b.WriteString(m.row(line, d.NetPL, i == m.cursor) + "\n")
+	}
+	for i := end - m.offset; i < m.visible(); i++ {
+		b.WriteString("\n")
+	}
+}
+
+func (m *model) viewTrades(b *strings.Builder) {
+	day := m.days[m.cursor]
+	rows := m.byDate[day.Date]
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("%-11s %-8s %-4s %7s %12s  %s", day.Date, "SYMBOL", "SIDE", "VOLUME", "NET P&L", "TAGS")) + "\n")
+
+	visible := m.visible() - 1 // one line for the selected trade's notes
+	m.tradeOff = clampOffset(m.tradeSel, m.tradeOff, visible)
+	end := min(m.tradeOff+visible, len(rows))
+	for i := m.tradeOff; i < end; i++ {
+		t := rows[i].Trade
+		net := t.GrossPL - t.Commission - t.Fees
+		status := startTime(t.StartDatetime)
+		if t.Open {
+			status = "open"
+		}
+		line := fmt.Sprintf("%-11s %-8s %-4s %7d %12s  %s", status, t.Symbol, t.Side, t.Volume, summary.FormatPL(net), strings.Join(t.Tags, ", "))
+		b.WriteString(m.row(line, net, i == m.tradeSel) + "\n")
+	}
+	for i := end - m.tradeOff; i < visible; i++ {
+		b.WriteString("\n")
+	}
+
+	notes := ""
+	if m.tradeSel < len(rows) {
+		notes = strings.Join(strings.Fields(rows[m.tradeSel].Trade.Notes), " ")
+	}
+	if notes == "" {
+		notes = helpStyle.Render("(no notes)")
+	}
+	b.WriteString(truncate(notes, m.width) + "\n")
+}
+
+// row renders a table line, colored by P&L or reversed when selected.
+func (m *model) row(line string, pl float64, selected bool) string {
+	line = truncate(line, m.width)
+	if selected {
+		return selectedStyle.Render(line)
+	}
+	switch {
+	case pl > 0:
+		return greenStyle.Render(line)
+	case pl < 0:
+		return redStyle.Render(line)
+	}
+	return line
+}
+
+// sparkline draws the equity curve of the filtered days, squeezed to the
+// screen width.
+func (m *model) sparkline() string {
+	points := summary.Equity(m.days).Points
+	if len(points) == 0 {
+		return helpStyle.Render("(no equity curve)")
+	}
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = p.Equity
+	}
+	return sparkline(values, max(m.width-2, 10))
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values as block characters scaled between their
+// minimum and maximum, sampling down to at most width of them.
+func sparkline(values []float64, width int) string {
+	if len(values) > width {
+		sampled := make([]float64, width)
+		for i := range sampled {
+			// The last value of each bucket, so the line ends on the final equity
+			sampled[i] = values[(i+1)*len(values)/width-1]
+		}
+		values = sampled
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = int((v - lo) / (hi - lo) * float64(len(sparks)-1))
+		}
+		b.WriteRune(sparks[level])
+	}
+	return b.String()
+}
+
+func startTime(datetime string) string {
+	// yyyy-mm-ddThh:mm:ss...; show hh:mm:ss
+	if len(datetime) >= 19 {
+		return datetime[11:19]
+	}
+	return datetime
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if width > 0 && len(r) > width {
+		return string(r[:width])
+	}
+	return s
+}
+
+func colorPL(v float64) string {
+	switch {
+	case v > 0:
+		return greenStyle.Render(summary.FormatPL(v))
+	case v < 0:
+		return redStyle.Render(summary.FormatPL(v))
+	}
+	return summary.FormatPL(v)
+}
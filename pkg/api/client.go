@@ -0,0 +1,563 @@
+// Package api is a client for the Tradervue REST API, with rate limiting
+// and retries on throttled or failed requests.
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+const (
+	baseURL    = "https://app.tradervue.com/api/v1"
+	maxPerPage = 100
+
+	// DefaultRequestDelay is the minimum spacing between API requests.
+	DefaultRequestDelay = 200 * time.Millisecond
+
+	// DefaultTimeout bounds each request, including reading the response.
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultMaxRetries is how many attempts a request gets before failing.
+	DefaultMaxRetries = 3
+
+	// DefaultRetryBase and DefaultRetryMax bound the exponential backoff
+	// between attempts: base, 2x base, 4x base, ... up to max.
+	DefaultRetryBase = 2 * time.Second
+	DefaultRetryMax  = 30 * time.Second
+
+	// DefaultMaxFailures is how many attempts in a row, across requests,
+	// may fail without a response (or with a server error) before the
+	// client stops sending requests at all.
+	DefaultMaxFailures = 20
+)
+
+// ErrForbidden is returned when Tradervue refuses a request with HTTP 403,
+// usually because the account's plan doesn't include API access.
+var ErrForbidden = errors.New("access denied")
+
+// ErrNotFound is returned for HTTP 404, such as executions requested for a
+// trade ID that doesn't exist (or no longer does).
+var ErrNotFound = errors.New("not found")
+
+// ErrAPIDown is returned once too many attempts in a row have failed (see
+// WithMaxFailures). Every later request fails with it straight away, so a
+// long export stops instead of retrying against a dead endpoint.
+var ErrAPIDown = errors.New("API appears down")
+
+// Authenticator applies credentials to an outgoing API request.
+type Authenticator interface {
+	Authenticate(req *http.Request)
+}
+
+// BasicAuth authenticates with a Tradervue username and password.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Authenticate sets the HTTP Basic Auth header.
+func (a BasicAuth) Authenticate(req *http.Request) {
+	req.SetBasicAuth(a.Username, a.Password)
+}
+
+// TokenAuth authenticates with a Tradervue API token.
+type TokenAuth struct {
+	Token string
+}
+
+// Authenticate sets the token as a bearer Authorization header.
+func (a TokenAuth) Authenticate(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+}
+
+// Client is the Tradervue API client.
+type Client struct {
+	auth         Authenticator
+	userAgent    string
+	httpClient   *http.Client
+	transport    *http.Transport   // base transport, configured by options
+	roundTripper http.RoundTripper // replaces transport when set (WithTransport)
+	timeout      time.Duration
+	debug        bool
+	logger       logging.Logger // where --debug output goes; nil means text on stderr
+	requestDelay time.Duration
+	maxRetries   int
+	retryBase    time.Duration
+	retryMax     time.Duration
+	maxFailures  int
+
+	mu      sync.Mutex // guards lastReq; held while waiting so callers queue up
+	lastReq time.Time
+
+	// Hooks for tests: random numbers in [0, 1) for the backoff jitter, and
+	// the wait used for backoff and rate limiting.
+	random func() float64
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	statsMu  sync.Mutex // guards stats and the failure streak
+	stats    Stats
+	failures int   // attempts failed in a row, across requests
+	downErr  error // set once failures reaches maxFailures
+}
+
+// Option configures optional Client behavior.
+type Option func(*Client)
+
+// WithRequestDelay sets the minimum spacing between API requests.
+// Accounts with a stricter rate limit need a longer delay.
+func WithRequestDelay(d time.Duration) Option {
+	return func(c *Client) {
+		c.requestDelay = d
+	}
+}
+
+// WithMaxRetries sets how many attempts a request gets before failing,
+// counting retries after server errors and HTTP 429 responses.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxRetries = n
+		}
+	}
+}
+
+// WithBackoff sets the first retry delay and the cap it doubles up to.
+// Non-positive values keep the defaults.
+func WithBackoff(base, max time.Duration) Option {
+	return func(c *Client) {
+		if base > 0 {
+			c.retryBase = base
+		}
+		if max > 0 {
+			c.retryMax = max
+		}
+	}
+}
+
+// WithMaxFailures sets how many attempts in a row may fail, across all
+// requests, before the client gives up with ErrAPIDown. Non-positive
+// values keep DefaultMaxFailures.
+func WithMaxFailures(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxFailures = n
+		}
+	}
+}
+
+// WithTransport sets the http.RoundTripper requests are sent through, e.g.
+// a stub in tests. It replaces the client's own transport, so proxy and CA
+// settings no longer apply. Nil keeps the client's transport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.roundTripper = rt
+	}
+}
+
+// WithTimeout sets how long a single request may take, including reading
+// the response. Non-positive values keep DefaultTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// WithRootCAs sets the certificate authorities trusted for TLS, for
+// networks that intercept HTTPS with their own root CA. Nil keeps the
+// system roots.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(c *Client) {
+		if pool != nil {
+			c.transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+		}
+	}
+}
+
+// WithDebug logs every request and response through a DebugTransport.
+func WithDebug() Option {
+	return func(c *Client) {
+		c.debug = true
+	}
+}
+
+// WithLogger sends the client's debug output to l instead of stderr.
+func WithLogger(l logging.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// NewClient creates a new Tradervue API client. Requests go through a
+// proxy when HTTPS_PROXY or HTTP_PROXY is set (NO_PROXY is honored).
+func NewClient(auth Authenticator, userAgent string, opts ...Option) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	c := &Client{
+		auth:         auth,
+		userAgent:    userAgent,
+		transport:    transport,
+		timeout:      DefaultTimeout,
+		requestDelay: DefaultRequestDelay,
+		maxRetries:   DefaultMaxRetries,
+		retryBase:    DefaultRetryBase,
+		retryMax:     DefaultRetryMax,
+		maxFailures:  DefaultMaxFailures,
+		random:       rand.Float64,
+		sleep:        sleepContext,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	var rt http.RoundTripper = c.transport
+	if c.roundTripper != nil {
+		rt = c.roundTripper
+	}
+	if c.debug {
+		rt = &DebugTransport{Next: rt, Logger: c.logger}
+	}
+	c.httpClient = &http.Client{Timeout: c.timeout, Transport: rt}
+	return c
+}
+
+// LoadCACert reads a PEM bundle of CA certificates and returns a pool
+// holding them along with the system roots.
+func LoadCACert(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificate: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// RequestDelay returns the minimum delay enforced between API requests.
+func (c *Client) RequestDelay() time.Duration {
+	return c.requestDelay
+}
+
+// tradesResponse wraps the API response for /trades.
+type tradesResponse struct {
+	Trades []models.Trade `json:"trades"`
+}
+
+// executionsResponse wraps the API response for /trades/{id}/executions.
+type executionsResponse struct {
+	Executions []models.Execution `json:"executions"`
+}
+
+// journalResponse wraps the API response for /journal.
+type journalResponse struct {
+	JournalEntries []models.JournalEntry `json:"journal_entries"`
+}
+
+// TradeFilter narrows a trade listing on Tradervue's side, so fewer pages
+// come back. Empty fields don't filter.
+type TradeFilter struct {
+	Symbol string // only trades in this symbol
+	Tag    string // only trades carrying this tag
+}
+
+// ListTrades fetches a page of trades with optional date filters.
+// Dates should be in mm/dd/yyyy format as required by Tradervue.
+func (c *Client) ListTrades(ctx context.Context, startDate, endDate string, page int) ([]models.Trade, error) {
+	return c.ListTradesFiltered(ctx, startDate, endDate, page, TradeFilter{})
+}
+
+// ListTradesFiltered is ListTrades with a symbol and tag filter applied by
+// Tradervue.
+func (c *Client) ListTradesFiltered(ctx context.Context, startDate, endDate string, page int, filter TradeFilter) ([]models.Trade, error) {
+	url := fmt.Sprintf("%s/trades?count=%d&page=%d", baseURL, maxPerPage, page)
+	if startDate != "" {
+		url += "&startdate=" + startDate
+	}
+	if endDate != "" {
+		url += "&enddate=" + endDate
+	}
+	if filter.Symbol != "" {
+		url += "&symbol=" + neturl.QueryEscape(filter.Symbol)
+	}
+	if filter.Tag != "" {
+		url += "&tag=" + neturl.QueryEscape(filter.Tag)
+	}
+
+	var resp tradesResponse
+	if err := c.doGet(ctx, url, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Trades, nil
+}
+
+// Ping checks that the API is reachable and accepts the credentials by
+// requesting a single trade.
+func (c *Client) Ping(ctx context.Context) error {
+	var resp tradesResponse
+	return c.doGet(ctx, baseURL+"/trades?count=1", &resp)
+}
+
+// GetExecutions fetches all executions for a given trade ID.
+func (c *Client) GetExecutions(ctx context.Context, tradeID int) ([]models.Execution, error) {
+	url := fmt.Sprintf("%s/trades/%d/executions", baseURL, tradeID)
+
+	var resp executionsResponse
+	if err := c.doGet(ctx, url, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Executions, nil
+}
+
+// ListJournal fetches a page of journal entries with optional date filters.
+func (c *Client) ListJournal(ctx context.Context, startDate, endDate string, page int) ([]models.JournalEntry, error) {
+	url := fmt.Sprintf("%s/journal?count=%d&page=%d", baseURL, maxPerPage, page)
+	if startDate != "" {
+		url += "&startdate=" + startDate
+	}
+	if endDate != "" {
+		url += "&enddate=" + endDate
+	}
+
+	var resp journalResponse
+	if err := c.doGet(ctx, url, &resp); err != nil {
+		return nil, err
+	}
+	return resp.JournalEntries, nil
+}
+
+// doGet performs an authenticated GET request with retry and rate limiting.
+// Cancelling ctx aborts the in-flight request and any pending retry wait.
+// An HTTP 204 or an empty body leaves result untouched, so list calls
+// return no items.
+func (c *Client) doGet(ctx context.Context, url string, result interface{}) error {
+	if err := c.down(); err != nil {
+		return err
+	}
+	if err := c.rateLimit(ctx); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	c.auth.Authenticate(req)
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	var lastErr error
+	var retryAfter time.Duration
+	for attempt := 0; attempt < c.maxRetries; attempt++ {
+		if attempt > 0 {
+			wait := c.backoff(attempt)
+			if retryAfter > 0 {
+				// The server told us how long to back off
+				wait = retryAfter
+				retryAfter = 0
+			}
+			waitStart := time.Now()
+			if err := c.sleep(ctx, wait); err != nil {
+				return err
+			}
+			c.count(func(s *Stats) {
+				s.Retries++
+				s.RetryWait += time.Since(waitStart)
+			})
+			if err := c.down(); err != nil {
+				return err
+			}
+		}
+
+		c.count(func(s *Stats) { s.Requests++ })
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = fmt.Errorf("request failed: %w", err)
+			if err := c.fail(lastErr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		c.count(func(s *Stats) { s.Bytes += int64(len(body)) })
+		if readErr != nil {
+			lastErr = fmt.Errorf("reading response: %w", readErr)
+			if err := c.fail(lastErr); err != nil {
+				return err
+			}
+			continue
+		}
+		if resp.StatusCode < 500 {
+			c.succeed()
+		}
+
+		switch {
+		case resp.StatusCode == 401:
+			return fmt.Errorf("authentication failed (HTTP 401): check your credentials")
+		case resp.StatusCode == 400:
+			return fmt.Errorf("bad request (HTTP 400): %s", string(body))
+		case resp.StatusCode == http.StatusForbidden:
+			return fmt.Errorf("%w (HTTP 403) for %s: the account's Tradervue plan may not include API access, or the account lacks permission; check the plan and API settings in Tradervue", ErrForbidden, req.URL.Path)
+		case resp.StatusCode == http.StatusNotFound:
+			return fmt.Errorf("%w (HTTP 404): %s", ErrNotFound, req.URL.Path)
+		case resp.StatusCode == http.StatusTooManyRequests:
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			c.count(func(s *Stats) { s.Throttled++ })
+			lastErr = fmt.Errorf("rate limited (HTTP 429): consider raising TVUE_REQUEST_DELAY")
+			continue
+		case resp.StatusCode >= 500:
+			lastErr = fmt.Errorf("server error (HTTP %d): %s", resp.StatusCode, string(body))
+			if err := c.fail(lastErr); err != nil {
+				return err
+			}
+			continue
+		case resp.StatusCode == http.StatusNoContent:
+			return nil
+		case resp.StatusCode != 200:
+			return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+		}
+
+		// A range with nothing in it sometimes comes back as a 200 with no
+		// body; leave result empty rather than failing to parse it
+		if len(bytes.TrimSpace(body)) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("request failed after %d attempts: %w", c.maxRetries, lastErr)
+}
+
+// down returns ErrAPIDown, with the failure that tripped it, once the
+// client has given up on the API.
+func (c *Client) down() error {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	return c.downErr
+}
+
+// fail records a failed attempt, returning ErrAPIDown when it makes
+// maxFailures in a row.
+func (c *Client) fail(err error) error {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	if c.downErr != nil {
+		return c.downErr
+	}
+	c.failures++
+	if c.failures >= c.maxFailures {
+		c.downErr = fmt.Errorf("%w: %d attempts in a row failed, stopping (last error: %v)", ErrAPIDown, c.failures, err)
+		return c.downErr
+	}
+	return nil
+}
+
+// succeed ends the failure streak: the API answered.
+func (c *Client) succeed() {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	if c.downErr == nil {
+		c.failures = 0
+	}
+}
+
+// backoff returns the jittered wait before the given retry attempt (1 for
+// the first retry). The step is retryBase doubled per attempt, capped at
+// retryMax, and the wait is a random point between half the step and the
+// whole of it, so parallel workers that fail together don't retry in
+// lockstep and no wait goes past retryMax.
+func (c *Client) backoff(attempt int) time.Duration {
+	step := c.retryMax
+	if attempt < 31 {
+		if d := c.retryBase << uint(attempt-1); d > 0 && d < c.retryMax {
+			step = d
+		}
+	}
+	half := step / 2
+	return half + time.Duration(float64(step-half)*c.random())
+}
+
+// sleepContext waits for d, or until ctx is cancelled, returning ctx's
+// error in that case.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// parseRetryAfter interprets a Retry-After header given either as a number
+// of seconds or as an HTTP date. It returns zero if the header is missing or
+// unparseable, in which case the normal backoff applies.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// rateLimit enforces a minimum delay between API requests. It is safe for
+// concurrent use: callers are serialized so requests stay evenly spaced.
+// Cancelling ctx ends the wait early with ctx's error.
+func (c *Client) rateLimit(ctx context.Context) error {
+	start := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer func() {
+		c.count(func(s *Stats) { s.RateLimitWait += time.Since(start) })
+	}()
+
+	if !c.lastReq.IsZero() {
+		if wait := c.requestDelay - time.Since(c.lastReq); wait > 0 {
+			if err := c.sleep(ctx, wait); err != nil {
+				return err
+			}
+		}
+	}
+	c.lastReq = time.Now()
+	return nil
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimitSpacesConcurrentRequests(t *testing.T) {
+	const (
+		delay   = 25 * time.Millisecond
+		workers = 8
+	)
+
+	var mu sync.Mutex
+	var arrivals []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		w.Write([]byte(`{"trades": []}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(BasicAuth{Username: "u", Password: "p"}, "test", WithRequestDelay(delay))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var resp tradesResponse
+			if err := c.doGet(context.Background(), srv.URL+"/trades", &resp); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("doGet: %v", err)
+	}
+
+	if len(arrivals) != workers {
+		t.Fatalf("server saw %d requests, want %d", len(arrivals), workers)
+	}
+	sort.Slice(arrivals, func(i, j int) bool { return arrivals[i].Before(arrivals[j]) })
+
+	// Allow a little for scheduling between the client's wait and the
+	// server noting the request
+	min := delay - 5*time.Millisecond
+	for i := 1; i < len(arrivals); i++ {
+		if gap := arrivals[i].Sub(arrivals[i-1]); gap < min {
+			t.Errorf("requests %d and %d arrived %v apart, want at least %v", i-1, i, gap, delay)
+		}
+	}
+}
+
+func TestRateLimitHonorsCancel(t *testing.T) {
+	c := NewClient(BasicAuth{}, "test", WithRequestDelay(time.Hour))
+	c.lastReq = time.Now()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- c.rateLimit(ctx) }()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Fatalf("rateLimit = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("rateLimit waited out the delay after ctx was cancelled")
+	}
+}
+
+// failingTransport answers every request with HTTP 500.
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Body:       io.NopCloser(strings.NewReader("boom")),
+		Header:     make(http.Header),
+		Request:    r,
+	}, nil
+}
+
+func TestBackoffSequence(t *testing.T) {
+	tests := []struct {
+		name   string
+		random float64
+		want   []time.Duration
+	}{
+		// Base 1s doubling to a 5s cap: steps of 1s, 2s, 4s, 5s, 5s
+		{"top of range", 0.999999999, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}},
+		{"bottom of range", 0, []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 2500 * time.Millisecond, 2500 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(BasicAuth{}, "test",
+				WithTransport(failingTransport{}),
+				WithRequestDelay(0),
+				WithMaxRetries(6),
+				WithMaxFailures(100),
+				WithBackoff(time.Second, 5*time.Second))
+			c.random = func() float64 { return tt.random }
+			var slept []time.Duration
+			c.sleep = func(ctx context.Context, d time.Duration) error {
+				slept = append(slept, d)
+				return nil
+			}
+
+			var resp tradesResponse
+			if err := c.doGet(context.Background(), "http://tradervue.test/trades", &resp); err == nil {
+				t.Fatal("doGet succeeded against a failing server")
+			}
+			if len(slept) != len(tt.want) {
+				t.Fatalf("slept %d times (%v), want %d", len(slept), slept, len(tt.want))
+			}
+			for i, d := range slept {
+				// The top of the range is just under the step
+				if diff := tt.want[i] - d; diff < 0 || diff > time.Millisecond {
+					t.Errorf("wait %d = %v, want %v", i+1, d, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffJitterBounds(t *testing.T) {
+	base := 2 * time.Second
+	c := NewClient(BasicAuth{}, "test", WithBackoff(base, time.Minute))
+	for attempt := 1; attempt <= 8; attempt++ {
+		step := base << uint(attempt-1)
+		if step > time.Minute {
+			step = time.Minute
+		}
+		for i := 0; i < 1000; i++ {
+			if d := c.backoff(attempt); d < step/2 || d > step {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", attempt, d, step/2, step)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+)
+
+// defaultLogger is where a DebugTransport without a Logger writes.
+var defaultLogger = logging.NewText(os.Stderr)
+
+// maxDebugBody caps how much of an error response body is logged.
+const maxDebugBody = 512
+
+// DebugTransport is an http.RoundTripper that logs each request's method,
+// URL, and headers, and each response's status and timing. The
+// Authorization header is redacted. Bodies of error responses are logged
+// too, since Tradervue explains most 400s there.
+type DebugTransport struct {
+	Next   http.RoundTripper // nil means http.DefaultTransport
+	Logger logging.Logger    // nil means text on stderr
+}
+
+// NewDebugTransport wraps next with request/response logging.
+func NewDebugTransport(next http.RoundTripper) *DebugTransport {
+	return &DebugTransport{Next: next}
+}
+
+// RoundTrip logs the request, sends it through the wrapped transport, and
+// logs the outcome.
+func (t *DebugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	t.logf("--> %s %s", req.Method, req.URL)
+	t.logf("    %s", redactedHeaders(req.Header))
+
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		t.logf("<-- %s %s failed after %s: %v", req.Method, req.URL, elapsed, err)
+		return nil, err
+	}
+
+	t.logf("<-- %s %s (%s)", resp.Status, req.URL, elapsed)
+
+	if resp.StatusCode >= 400 && resp.Body != nil {
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxDebugBody))
+		// Put back what was read so the client still sees the full body
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		if readErr == nil && len(body) > 0 {
+			t.logf("    body: %s", strings.TrimSpace(string(body)))
+		}
+	}
+
+	return resp, nil
+}
+
+func (t *DebugTransport) logf(format string, args ...any) {
+	l := t.Logger
+	if l == nil {
+		l = defaultLogger
+	}
+	l.Debugf(format, args...)
+}
+
+// redactedHeaders renders headers in a stable order, hiding credentials.
+func redactedHeaders(h http.Header) string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := strings.Join(h[k], ", ")
+		if strings.EqualFold(k, "Authorization") {
+			v = "[REDACTED]"
+		}
+		parts = append(parts, k+": "+v)
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,28 @@
+package api
+
+import "time"
+
+// Stats counts the client's outbound traffic. Waits are summed over all
+// callers, so with parallel workers they can exceed the wall-clock time.
+type Stats struct {
+	Requests      int           // HTTP requests sent, retries included
+	Bytes         int64         // response body bytes read
+	Retries       int           // attempts after the first, for any reason
+	Throttled     int           // HTTP 429 responses
+	RateLimitWait time.Duration // time spent queued for the request delay
+	RetryWait     time.Duration // time spent backing off between attempts
+}
+
+// Stats returns the traffic counted since the client was created.
+func (c *Client) Stats() Stats {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	return c.stats
+}
+
+// count applies f to the client's stats.
+func (c *Client) count(f func(s *Stats)) {
+	c.statsMu.Lock()
+	f(&c.stats)
+	c.statsMu.Unlock()
+}
@@ -0,0 +1,199 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// dateRange is an inclusive span of consecutive days.
+type dateRange struct {
+	start, end time.Time
+}
+
+// backfill walks from the first trade date to the last export date, finds
+// days with no day file or only a partial one, and re-fetches only those. Days that turn out to
+// have no trades are remembered in the state so later runs skip them.
+func (e *Exporter) backfill(ctx context.Context, opts Options, state *models.ExportState) error {
+	if state == nil || state.FirstTradeDate == "" || state.LastExportDate == "" {
+		return fmt.Errorf("nothing to verify: run an export first")
+	}
+
+	first, err := time.Parse(fileDateFmt, state.FirstTradeDate)
+	if err != nil {
+		return fmt.Errorf("corrupt state file: %w", err)
+	}
+	last, err := time.Parse(fileDateFmt, state.LastExportDate)
+	if err != nil {
+		return fmt.Errorf("corrupt state file: %w", err)
+	}
+
+	existing, err := e.existingDates()
+	if err != nil {
+		return err
+	}
+	// A partial file may be missing trades, so its day counts as a gap
+	partial, err := e.partialDates()
+	if err != nil {
+		return err
+	}
+	for d := range partial {
+		delete(existing, d)
+	}
+	knownEmpty := make(map[string]bool, len(state.KnownEmptyDays))
+	for _, d := range state.KnownEmptyDays {
+		knownEmpty[d] = true
+	}
+
+	// Collect runs of consecutive missing days so each gap is one query
+	var gaps []dateRange
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		key := d.Format(fileDateFmt)
+		if existing[key] || knownEmpty[key] {
+			continue
+		}
+		if n := len(gaps); n > 0 && gaps[n-1].end.AddDate(0, 0, 1).Equal(d) {
+			gaps[n-1].end = d
+		} else {
+			gaps = append(gaps, dateRange{start: d, end: d})
+		}
+	}
+
+	if len(gaps) == 0 {
+		e.logger.Infof("Verified %s to %s: no missing days.", state.FirstTradeDate, state.LastExportDate)
+		return nil
+	}
+
+	e.infof("Verifying %s to %s: checking %d gaps...", state.FirstTradeDate, state.LastExportDate, len(gaps))
+
+	if opts.DryRun {
+		for _, gap := range gaps {
+			e.logger.Infof("  would check %s to %s", gap.start.Format(fileDateFmt), gap.end.Format(fileDateFmt))
+		}
+		e.logger.Infof("Dry run: %d gaps would be re-fetched. Nothing was written.", len(gaps))
+		return nil
+	}
+
+	var backfilled []string
+	totalTrades := 0
+	var runErr error
+
+gapLoop:
+	for _, gap := range gaps {
+		trades, err := e.fetchAllTrades(ctx, gap.start, gap.end, opts.Quiet)
+		if err != nil {
+			runErr = err
+			break
+		}
+		byDate := e.groupTradesByDate(trades)
+
+		for d := gap.start; !d.After(gap.end); d = d.AddDate(0, 0, 1) {
+			key := d.Format(fileDateFmt)
+			dayTrades, ok := byDate[key]
+			if !ok {
+				knownEmpty[key] = true
+				continue
+			}
+
+			if err := e.exportDay(ctx, key, dayTrades, opts); err != nil {
+				if ctx.Err() != nil {
+					runErr = err
+					break gapLoop
+				}
+				// Left as a gap, so the next --verify retries it
+				e.warn(key, "%v", err)
+				continue
+			}
+			backfilled = append(backfilled, key)
+			totalTrades += len(dayTrades)
+		}
+	}
+
+	// Record progress even when interrupted, so finished gaps aren't redone
+	state.KnownEmptyDays = state.KnownEmptyDays[:0]
+	for d := range knownEmpty {
+		state.KnownEmptyDays = append(state.KnownEmptyDays, d)
+	}
+	sort.Strings(state.KnownEmptyDays)
+	state.TotalTrades += totalTrades
+	state.TotalDays += len(backfilled)
+	state.LastRunAt = time.Now()
+	e.recordOpenDays(state)
+	e.recordSettings(state, opts)
+
+	if err := e.saveState(state); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+
+	if runErr != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("verify interrupted: %w", context.Cause(ctx))
+		}
+		return runErr
+	}
+
+	if len(backfilled) == 0 {
+		e.logger.Infof("Verify complete: no trades were missing.")
+		return nil
+	}
+
+	e.logger.Infof("Verify complete: backfilled %d days, %d trades: %s",
+		len(backfilled), totalTrades, strings.Join(backfilled, ", "))
+	return nil
+}
+
+// existingDates returns the set of dates that have a day file on disk, in
+// either form.
+func (e *Exporter) existingDates() (map[string]bool, error) {
+	files, err := dayfile.List(e.dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := make(map[string]bool, len(files))
+	for _, f := range files {
+		dates[f.Date] = true
+	}
+	return dates, nil
+}
+
+// completeDates returns the dates whose day file is on disk and recorded
+// in the manifest as complete. Partial files, and files written before
+// manifests existed, are left out.
+func (e *Exporter) completeDates() (map[string]bool, error) {
+	dates, err := e.existingDates()
+	if err != nil {
+		return nil, err
+	}
+	m, err := loadManifest(e.dataDir)
+	if err != nil {
+		return nil, err
+	}
+	for d := range dates {
+		if entry, ok := m.Days[d]; !ok || entry.Partial {
+			delete(dates, d)
+		}
+	}
+	return dates, nil
+}
+
+// partialDates returns the dates the manifest records a partial day file
+// for.
+func (e *Exporter) partialDates() (map[string]bool, error) {
+	m, err := loadManifest(e.dataDir)
+	if err != nil {
+		return nil, err
+	}
+	dates := make(map[string]bool)
+	for d, entry := range m.Days {
+		if entry.Partial {
+			dates[d] = true
+		}
+	}
+	return dates, nil
+}
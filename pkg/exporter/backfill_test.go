@@ -0,0 +1,53 @@
+package exporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+func TestCompleteDates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, dayfile.Dir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	e := New(nil, dir)
+	e.SetLogger(logging.Discard)
+
+	trades := []models.Trade{{ID: 1, Symbol: "AAPL"}}
+	for date, partial := range map[string]bool{"2025-01-02": false, "2025-01-03": true} {
+		day := &models.DayExport{Date: date, Trades: trades, ExportedAt: time.Now(), Partial: partial}
+		if err := e.saveDayExport(day, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A day file from before the manifest existed
+	data, err := dayfile.Encode(&models.DayExport{Date: "2025-01-06", Trades: trades}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dayfile.Path(dir, "2025-01-06", false), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	complete, err := e.completeDates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(complete) != 1 || !complete["2025-01-02"] {
+		t.Errorf("completeDates = %v, want only 2025-01-02", complete)
+	}
+
+	partial, err := e.partialDates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(partial) != 1 || !partial["2025-01-03"] {
+		t.Errorf("partialDates = %v, want only 2025-01-03", partial)
+	}
+}
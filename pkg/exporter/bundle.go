@@ -0,0 +1,130 @@
+package exporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// Bundle writes the day files in dataDir dated from to to (either may be
+// empty for no bound) to w as a single JSON array of day exports, oldest
+// first, one day per line. Days are read and written one at a time, so
+// memory use stays that of the largest day however many there are. It
+// returns the number of days written. Each day is dated by its file name,
+// so Unbundle puts it back where it was found.
+func Bundle(w io.Writer, dataDir, from, to string) (int, error) {
+	files, err := dayfile.List(dataDir)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, f := range files {
+		if (from != "" && f.Date < from) || (to != "" && f.Date > to) {
+			continue
+		}
+		day, err := dayfile.Load(f.Path)
+		if err != nil {
+			return n, fmt.Errorf("reading %s: %w", f.Date, err)
+		}
+		// The file name is what the day is stored under; a hand-edited or
+		// older file may carry another date, or none, inside
+		day.Date = f.Date
+		data, err := json.Marshal(day)
+		if err != nil {
+			return n, fmt.Errorf("encoding %s: %w", f.Date, err)
+		}
+
+		sep := "\n"
+		if n > 0 {
+			sep = ",\n"
+		}
+		if _, err := io.WriteString(w, sep); err != nil {
+			return n, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if _, err := io.WriteString(w, "\n]\n"); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// UnbundleOptions controls how a bundle is split back into day files.
+type UnbundleOptions struct {
+	Compress  bool // write day files gzipped
+	Overwrite bool // replace day files that already exist instead of skipping them
+}
+
+// UnbundleResult counts what Unbundle did.
+type UnbundleResult struct {
+	Written int
+	Skipped []string // dates left alone because a day file already existed
+}
+
+// Unbundle reads a JSON array of day exports, as written by Bundle, and
+// writes each one to its day file, updating the manifest as an export
+// would. The array is decoded one day at a time rather than all at once.
+// Like Run, it holds the data directory's lock file while it works.
+func (e *Exporter) Unbundle(r io.Reader, opts UnbundleOptions) (*UnbundleResult, error) {
+	unlock, err := e.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := os.MkdirAll(filepath.Join(e.dataDir, dayfile.Dir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create trades directory: %w", err)
+	}
+
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("reading bundle: %w", err)
+	} else if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("reading bundle: expected a JSON array of day exports")
+	}
+
+	result := &UnbundleResult{}
+	for i := 0; dec.More(); i++ {
+		var day models.DayExport
+		if err := dec.Decode(&day); err != nil {
+			return result, fmt.Errorf("reading bundle entry %d: %w", i+1, err)
+		}
+		if _, err := time.Parse(fileDateFmt, day.Date); err != nil {
+			return result, fmt.Errorf("bundle entry %d has invalid date %q", i+1, day.Date)
+		}
+
+		if !opts.Overwrite {
+			if _, err := dayfile.Find(e.dataDir, day.Date); err == nil {
+				result.Skipped = append(result.Skipped, day.Date)
+				continue
+			}
+		}
+
+		if err := e.saveDayExport(&day, opts.Compress); err != nil {
+			return result, fmt.Errorf("saving %s: %w", day.Date, err)
+		}
+		if err := e.updateSummaryCache(day.Date, day.Trades, false); err != nil {
+			return result, fmt.Errorf("clearing cached summary for %s: %w", day.Date, err)
+		}
+		result.Written++
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return result, fmt.Errorf("reading bundle: %w", err)
+	}
+	return result, nil
+}
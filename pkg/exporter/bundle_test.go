@@ -0,0 +1,65 @@
+package exporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+)
+
+func TestBundleDatesDaysByFileName(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, dayfile.Dir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// One file with no date inside and one whose date disagrees with its name
+	files := map[string]string{
+		"2025-01-02": `{"trades": [{"id": 1, "symbol": "AAPL"}]}`,
+		"2025-01-03": `{"date": "2024-12-31", "trades": [{"id": 2, "symbol": "TSLA"}]}`,
+	}
+	for date, body := range files {
+		if err := os.WriteFile(dayfile.Path(src, date, false), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	n, err := Bundle(&buf, src, "", "")
+	if err != nil {
+		t.Fatalf("Bundle: %v", err)
+	}
+	if n != len(files) {
+		t.Fatalf("Bundle wrote %d days, want %d", n, len(files))
+	}
+
+	dst := t.TempDir()
+	e := New(nil, dst)
+	e.SetLogger(logging.Discard)
+	res, err := e.Unbundle(&buf, UnbundleOptions{})
+	if err != nil {
+		t.Fatalf("Unbundle: %v", err)
+	}
+	if res.Written != len(files) {
+		t.Fatalf("Unbundle wrote %d days, want %d", res.Written, len(files))
+	}
+
+	for date := range files {
+		f, err := dayfile.Find(dst, date)
+		if err != nil {
+			t.Fatalf("no day file for %s after the round trip: %v", date, err)
+		}
+		day, err := dayfile.Load(f.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if day.Date != date {
+			t.Errorf("%s: day file says date %q", date, day.Date)
+		}
+	}
+	if _, err := dayfile.Find(dst, "2024-12-31"); err == nil {
+		t.Error("day wrongly written under the date inside its file")
+	}
+}
@@ -0,0 +1,434 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// csvColumns maps each trade field to the CSV headers it is read from.
+// Headers are matched ignoring case, spaces, and punctuation, so "Gross P&L"
+// matches grosspl. Tradervue's own export headers come first; the JSON
+// field names of a day file are accepted too.
+var csvColumns = map[string][]string{
+	"id":          {"tradeid", "id"},
+	"symbol":      {"symbol", "ticker"},
+	"side":        {"side"},
+	"volume":      {"volume", "quantity", "qty", "shares"},
+	"open":        {"open"},
+	"start":       {"opendatetime", "startdatetime", "entrydatetime", "opened"},
+	"end":         {"closedatetime", "enddatetime", "exitdatetime", "closed"},
+	"entry":       {"entryprice"},
+	"exit":        {"exitprice"},
+	"gross":       {"grosspl"},
+	"net":         {"netpl"},
+	"commission":  {"totalcommissions", "commissions", "commission"},
+	"fees":        {"totalfees", "fees"},
+	"commfees":    {"commissionsfees", "commissionfees", "commfees"},
+	"notes":       {"notes"},
+	"tags":        {"tags"},
+	"shared":      {"shared"},
+	"risk":        {"initialrisk"},
+	"execs":       {"execcount", "executions"},
+	"positionmfe": {"positionmfe"},
+	"positionmae": {"positionmae"},
+	"pricemfe":    {"pricemfe"},
+	"pricemae":    {"pricemae"},
+	"bestexit":    {"bestexitpl"},
+}
+
+// csvTimeLayouts are the date formats accepted in the datetime columns.
+// Those without a zone are read in the import's timezone.
+var csvTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+	"1/2/2006 15:04:05",
+	"1/2/2006 15:04",
+	"1/2/2006 3:04:05 PM",
+	"1/2/2006 3:04 PM",
+	"2006-01-02",
+	"1/2/2006",
+}
+
+// ParseTradesCSV reads trades from a CSV file downloaded from Tradervue.
+// The symbol, open datetime, and gross (or net) P&L columns are required;
+// the others are filled in when present. Times without a zone are read in
+// loc. Rows without a trade ID get a stable negative one derived from the
+// trade itself, so importing the same file twice gives the same IDs.
+func ParseTradesCSV(r io.Reader, loc *time.Location) ([]models.Trade, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("the CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	col := make(map[string]int)
+	for i, h := range header {
+		key := normalizeHeader(h)
+		for field, names := range csvColumns {
+			for _, name := range names {
+				if key == name {
+					if _, ok := col[field]; !ok {
+						col[field] = i
+					}
+				}
+			}
+		}
+	}
+	for field, name := range map[string]string{"symbol": "Symbol", "start": "Open Datetime"} {
+		if _, ok := col[field]; !ok {
+			return nil, fmt.Errorf("the CSV file has no %s column (headers: %s)", name, strings.Join(header, ", "))
+		}
+	}
+	_, hasGross := col["gross"]
+	_, hasNet := col["net"]
+	if !hasGross && !hasNet {
+		return nil, fmt.Errorf("the CSV file has no Gross P&L or Net P&L column (headers: %s)", strings.Join(header, ", "))
+	}
+
+	var trades []models.Trade
+	ids := make(map[int]bool)
+	for row := 2; ; row++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		if blankRecord(rec) {
+			continue
+		}
+
+		t, err := parseCSVTrade(header, rec, col, loc)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		if t.ID == 0 {
+			t.ID = syntheticID(t)
+			for ids[t.ID] {
+				t.ID--
+			}
+		}
+		ids[t.ID] = true
+		trades = append(trades, t)
+	}
+	return trades, nil
+}
+
+// parseCSVTrade builds a trade from one CSV record.
+func parseCSVTrade(header, rec []string, col map[string]int, loc *time.Location) (models.Trade, error) {
+	get := func(field string) string {
+		i, ok := col[field]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+	amount := func(field string) (*float64, error) {
+		s := get(field)
+		if s == "" {
+			return nil, nil
+		}
+		v, err := parseAmount(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", header[col[field]], s)
+		}
+		return &v, nil
+	}
+
+	var t models.Trade
+	if s := get("id"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return t, fmt.Errorf("invalid trade ID %q", s)
+		}
+		t.ID = id
+	}
+
+	t.Symbol = strings.ToUpper(get("symbol"))
+	switch side := strings.ToUpper(get("side")); {
+	case side == "":
+	case strings.HasPrefix(side, "L"), strings.HasPrefix(side, "B"):
+		t.Side = "L"
+	case strings.HasPrefix(side, "S"):
+		t.Side = "S"
+	default:
+		return t, fmt.Errorf("invalid side %q (use Long or Short)", get("side"))
+	}
+
+	start, err := parseCSVTime(get("start"), loc)
+	if err != nil {
+		return t, fmt.Errorf("invalid open datetime: %w", err)
+	}
+	t.StartDatetime = start.Format(time.RFC3339)
+	if s := get("end"); s != "" {
+		end, err := parseCSVTime(s, loc)
+		if err != nil {
+			return t, fmt.Errorf("invalid close datetime: %w", err)
+		}
+		e := end.Format(time.RFC3339)
+		t.EndDatetime = &e
+		t.Duration = "I"
+		if end.In(loc).Format(fileDateFmt) != start.In(loc).Format(fileDateFmt) {
+			t.Duration = "M"
+		}
+	}
+
+	if s := get("volume"); s != "" {
+		v, err := parseAmount(s)
+		if err != nil {
+			return t, fmt.Errorf("invalid volume %q", s)
+		}
+		t.Volume = int(v)
+	}
+	if s := get("execs"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return t, fmt.Errorf("invalid exec count %q", s)
+		}
+		t.ExecCount = n
+	}
+
+	amounts := map[string]**float64{
+		"exit":        &t.ExitPrice,
+		"risk":        &t.InitialRisk,
+		"positionmfe": &t.PositionMFE,
+		"positionmae": &t.PositionMAE,
+		"pricemfe":    &t.PriceMFE,
+		"pricemae":    &t.PriceMAE,
+		"bestexit":    &t.BestExitPL,
+	}
+	for field, dst := range amounts {
+		if *dst, err = amount(field); err != nil {
+			return t, err
+		}
+	}
+	values := map[string]*float64{
+		"entry":      &t.EntryPrice,
+		"gross":      &t.GrossPL,
+		"commission": &t.Commission,
+		"fees":       &t.Fees,
+	}
+	for field, dst := range values {
+		v, err := amount(field)
+		if err != nil {
+			return t, err
+		}
+		if v != nil {
+			*dst = *v
+		}
+	}
+	// A single commissions-and-fees column goes in Commission
+	if _, ok := col["commission"]; !ok {
+		v, err := amount("commfees")
+		if err != nil {
+			return t, err
+		}
+		if v != nil {
+			t.Commission = *v
+		}
+	}
+	// Without a gross column, gross P&L is net P&L plus what it cost
+	if _, ok := col["gross"]; !ok {
+		net, err := amount("net")
+		if err != nil {
+			return t, err
+		}
+		if net != nil {
+			t.GrossPL = *net + t.Commission + t.Fees
+		}
+	}
+
+	t.Open = t.EndDatetime == nil && t.ExitPrice == nil
+	if s := get("open"); s != "" {
+		open, err := parseCSVBool(s)
+		if err != nil {
+			return t, fmt.Errorf("invalid open %q", s)
+		}
+		t.Open = open
+	}
+	if s := get("shared"); s != "" {
+		shared, err := parseCSVBool(s)
+		if err != nil {
+			return t, fmt.Errorf("invalid shared %q", s)
+		}
+		t.Shared = shared
+	}
+
+	t.Notes = get("notes")
+	t.NotesExcerpt = excerpt(t.Notes)
+	t.Tags = []string{}
+	for _, tag := range strings.Split(get("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			t.Tags = append(t.Tags, tag)
+		}
+	}
+	return t, nil
+}
+
+// parseCSVTime parses a datetime in one of csvTimeLayouts.
+func parseCSVTime(s string, loc *time.Location) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, errors.New("missing")
+	}
+	for _, layout := range csvTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date and time Tradervue writes (e.g. 2025-01-15 09:31:00 or 01/15/2025 09:31)", s)
+}
+
+// parseAmount parses a number as spreadsheets write it: "$1,234.50",
+// "-12.5", or "(12.50)" for a negative amount.
+func parseAmount(s string) (float64, error) {
+	neg := strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")")
+	if neg {
+		s = s[1 : len(s)-1]
+	}
+	s = strings.NewReplacer("$", "", ",", "", " ", "").Replace(s)
+	v, err := strconv.ParseFloat(s, 64)
+	if neg {
+		v = -v
+	}
+	return v, err
+}
+
+// parseCSVBool accepts true/false, yes/no, y/n, and 1/0.
+func parseCSVBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "y", "1":
+		return true, nil
+	case "false", "no", "n", "0":
+		return false, nil
+	}
+	return false, errors.New("not a boolean")
+}
+
+// normalizeHeader lowercases h and drops everything but letters and digits,
+// including a byte order mark left by spreadsheet programs.
+func normalizeHeader(h string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, h)
+}
+
+// blankRecord reports whether every field in rec is empty.
+func blankRecord(rec []string) bool {
+	for _, f := range rec {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// excerpt returns the first line of notes, cut to 100 characters, as
+// Tradervue's notes_excerpt holds.
+func excerpt(notes string) string {
+	line, _, _ := strings.Cut(notes, "\n")
+	if r := []rune(line); len(r) > 100 {
+		return string(r[:100])
+	}
+	return line
+}
+
+// syntheticID derives a negative trade ID from what identifies the trade,
+// so it can't collide with a real Tradervue ID.
+func syntheticID(t models.Trade) int {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s|%s|%s|%d|%g", t.Symbol, t.Side, t.StartDatetime, t.Volume, t.EntryPrice)
+	return -int(h.Sum32()&0x7fffffff) - 1
+}
+
+// ImportOptions controls how trades from a CSV file are written.
+type ImportOptions struct {
+	Timezone  string // zone days are grouped in and zoneless times read in; empty means DefaultTimezone
+	Compress  bool   // write day files gzipped
+	Overwrite bool   // replace day files that already exist instead of skipping them
+}
+
+// ImportResult counts what ImportCSV did.
+type ImportResult struct {
+	Written int      // day files written
+	Trades  int      // trades in them
+	Skipped []string // dates left alone because a day file already existed
+}
+
+// ImportCSV reads trades from a Tradervue CSV download (see
+// ParseTradesCSV) and writes a day file for each day they fall on, as an
+// export would. An overwritten day file is replaced outright rather than
+// merged, since CSV trades may lack Tradervue's IDs; only its journal entry
+// is kept. Like Unbundle, it leaves state.json alone, and like Run, it
+// holds the data directory's lock file while it works.
+func (e *Exporter) ImportCSV(r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	unlock, err := e.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	e.useTimezone(opts.Timezone)
+
+	trades, err := ParseTradesCSV(r, e.loc)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(e.dataDir, dayfile.Dir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create trades directory: %w", err)
+	}
+
+	result := &ImportResult{}
+	byDate := e.groupTradesByDate(trades)
+	for _, date := range sortedKeys(byDate) {
+		day := &models.DayExport{Date: date, Trades: byDate[date], ExportedAt: time.Now()}
+
+		old, _, err := e.loadDayExport(date)
+		if err == nil {
+			if !opts.Overwrite {
+				result.Skipped = append(result.Skipped, date)
+				continue
+			}
+			day.Journal = old.Journal
+		} else if !opts.Overwrite && !errors.Is(err, os.ErrNotExist) {
+			// Unreadable, but there; leave it for --force or doctor
+			result.Skipped = append(result.Skipped, date)
+			continue
+		}
+
+		if err := e.saveDayExport(day, opts.Compress); err != nil {
+			return result, fmt.Errorf("saving %s: %w", date, err)
+		}
+		if err := e.updateSummaryCache(date, day.Trades, false); err != nil {
+			return result, fmt.Errorf("clearing cached summary for %s: %w", date, err)
+		}
+		result.Written++
+		result.Trades += len(day.Trades)
+	}
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// discoveryStart is the earliest date discovery looks for trades from.
+var discoveryStart = time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// errProbeEmpty means a probe of the whole discovery range found nothing.
+var errProbeEmpty = errors.New("no trades in probe range")
+
+// discoverFirstTradeDate finds the oldest trade in the account. It
+// binary-searches the date range first, and falls back to paging through
+// the whole account if the probes give an inconsistent answer.
+func (e *Exporter) discoverFirstTradeDate(ctx context.Context, quiet bool) (time.Time, error) {
+	first, err := e.bisectFirstTradeDate(ctx)
+	if err == nil {
+		return first, nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return time.Time{}, fmt.Errorf("discovering first trade: %w", ctxErr)
+	}
+	if !errors.Is(err, errProbeEmpty) {
+		e.logger.Warnf("fast discovery failed (%v); scanning all pages instead", err)
+	}
+	return e.scanFirstTradeDate(ctx, quiet)
+}
+
+// bisectFirstTradeDate finds the first trading day by probing ranges that
+// end at a midpoint date: a probe with any trades means the first trade is
+// on or before it. This takes about a dozen requests instead of one per
+// page of trade history.
+func (e *Exporter) bisectFirstTradeDate(ctx context.Context) (time.Time, error) {
+	startStr := discoveryStart.Format(tvDateFmt)
+	hasTrades := func(end time.Time) (bool, error) {
+		trades, err := e.client.ListTrades(ctx, startStr, end.Format(tvDateFmt), 1)
+		if err != nil {
+			return false, err
+		}
+		return len(trades) > 0, nil
+	}
+
+	now := time.Now()
+	lo := discoveryStart
+	hi := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	ok, err := hasTrades(hi)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !ok {
+		return time.Time{}, errProbeEmpty
+	}
+
+	// Invariant: the range ending at hi has trades; none ends before lo.
+	for lo.Before(hi) {
+		days := int(hi.Sub(lo).Hours() / 24)
+		mid := lo.AddDate(0, 0, days/2)
+		ok, err := hasTrades(mid)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid.AddDate(0, 0, 1)
+		}
+	}
+
+	// Confirm against the trades on that day, which also gives the date in
+	// the configured timezone.
+	trades, err := e.fetchAllTrades(ctx, hi, hi, true)
+	if err != nil {
+		return time.Time{}, err
+	}
+	first, ok := e.earliestTradeDate(trades)
+	if !ok {
+		return time.Time{}, fmt.Errorf("probe found trades up to %s but none on that day", hi.Format(fileDateFmt))
+	}
+
+	// A result far from the probed day means the date filters weren't honored
+	if diff := first.Sub(hi); diff < -48*time.Hour || diff > 48*time.Hour {
+		return time.Time{}, fmt.Errorf("date filters not honored (probed %s, got %s)",
+			hi.Format(fileDateFmt), first.Format(fileDateFmt))
+	}
+
+	return first, nil
+}
+
+// earliestTradeDate returns the earliest start time among trades.
+func (e *Exporter) earliestTradeDate(trades []models.Trade) (time.Time, bool) {
+	var first time.Time
+	found := false
+	for _, t := range trades {
+		start, err := e.parseTradeDate(t.StartDatetime)
+		if err != nil {
+			continue
+		}
+		if !found || start.Before(first) {
+			first = start
+			found = true
+		}
+	}
+	return first, found
+}
+
+// scanFirstTradeDate finds the oldest trade by paging through the whole
+// account. It is the slow fallback for bisectFirstTradeDate.
+func (e *Exporter) scanFirstTradeDate(ctx context.Context, quiet bool) (time.Time, error) {
+	// Fetch trades without date filter to get the total count.
+	// Tradervue returns newest first, so we paginate to the last page.
+	page := 1
+	var oldest models.Trade
+	var found bool
+
+	prog := e.newProgress("Scanning", quiet)
+	defer prog.finish()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return time.Time{}, fmt.Errorf("discovering first trade: %w", err)
+		}
+		trades, err := e.client.ListTrades(ctx, discoveryStart.Format(tvDateFmt), "", page)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("discovering first trade: %w", err)
+		}
+		if len(trades) == 0 {
+			break
+		}
+		// The last item on the last page is the oldest trade
+		oldest = trades[len(trades)-1]
+		found = true
+		prog.page(len(trades), -1)
+
+		if len(trades) < 100 {
+			// This was the last page
+			break
+		}
+		page++
+	}
+
+	if !found {
+		return time.Time{}, fmt.Errorf("no trades found in your Tradervue account")
+	}
+
+	// Parse the start_datetime to extract the date
+	return e.parseTradeDate(oldest.StartDatetime)
+}
@@ -0,0 +1,51 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// LoadState reads the export state in dataDir. It returns nil and no error
+// when nothing has been exported yet.
+func LoadState(dataDir string) (*models.ExportState, error) {
+	state, err := (&Exporter{dataDir: dataDir}).loadState()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return state, err
+}
+
+// CheckDayFiles parses every day file in dataDir and checks that the date
+// inside matches the filename and that no date has two files. It returns
+// how many files were checked and the ones that failed.
+func CheckDayFiles(dataDir string) (int, []ManifestProblem, error) {
+	files, err := dayfile.List(dataDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil, nil
+		}
+		return 0, nil, err
+	}
+
+	var problems []ManifestProblem
+	for _, f := range files {
+		for _, path := range f.Shadowed {
+			problems = append(problems, ManifestProblem{Date: f.Date, Problem: fmt.Sprintf("older duplicate %s is ignored; remove it", filepath.Base(path))})
+		}
+		day, err := dayfile.Load(f.Path)
+		if err != nil {
+			problems = append(problems, ManifestProblem{Date: f.Date, Problem: "does not parse: " + err.Error()})
+			continue
+		}
+		if day.Date != f.Date {
+			problems = append(problems, ManifestProblem{Date: f.Date, Problem: fmt.Sprintf("file holds date %q", day.Date)})
+		}
+	}
+
+	return len(files), problems, nil
+}
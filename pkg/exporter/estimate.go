@@ -0,0 +1,96 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// approxExecutionBytes is the typical size of one pretty-printed execution
+// inside a day file, used to project the size of --with-executions exports.
+const approxExecutionBytes = 220
+
+// Estimate is a projection of the cost of an export, computed without
+// writing any files.
+type Estimate struct {
+	StartDate         string
+	EndDate           string
+	Days              int
+	Trades            int
+	Executions        int
+	PageRequests      int
+	ExecutionRequests int
+	Duration          time.Duration
+	Bytes             int64
+}
+
+// TotalRequests returns the number of API requests the export would make.
+func (est *Estimate) TotalRequests() int {
+	return est.PageRequests + est.ExecutionRequests
+}
+
+// Estimate resolves the export range and counts the trades in it, then
+// projects request count, wall-clock time, and output size for a real export
+// with the same options. Only trade pages are fetched; executions are not.
+func (e *Exporter) Estimate(ctx context.Context, opts Options) (*Estimate, error) {
+	e.level = opts.level()
+	e.useTimezone(opts.Timezone)
+
+	state, _ := e.loadState()
+
+	startDate, endDate, err := e.resolveRange(ctx, opts, state)
+	if err != nil {
+		return nil, err
+	}
+
+	est := &Estimate{
+		StartDate: startDate.Format(fileDateFmt),
+		EndDate:   endDate.Format(fileDateFmt),
+	}
+	if startDate.After(endDate) {
+		return est, nil
+	}
+
+	began := time.Now()
+	trades, err := e.fetchAllTrades(ctx, startDate, endDate, opts.Quiet)
+	if err != nil {
+		return nil, err
+	}
+	elapsed := time.Since(began)
+
+	est.Trades = len(trades)
+	est.PageRequests = len(trades)/100 + 1
+	if opts.WithExecutions {
+		est.ExecutionRequests = len(trades)
+	}
+
+	byDate := e.groupTradesByDate(trades)
+	est.Days = len(byDate)
+	for date, dayTrades := range byDate {
+		data, err := json.MarshalIndent(&models.DayExport{Date: date, Trades: dayTrades, ExportedAt: time.Now()}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("sizing %s: %w", date, err)
+		}
+		est.Bytes += int64(len(data))
+
+		for _, t := range dayTrades {
+			est.Executions += t.ExecCount
+		}
+	}
+	if opts.WithExecutions {
+		est.Bytes += int64(est.Executions) * approxExecutionBytes
+	}
+
+	// Project time from the observed per-request latency, never faster than
+	// the client's rate limit allows.
+	perRequest := elapsed / time.Duration(est.PageRequests)
+	if perRequest < e.client.RequestDelay() {
+		perRequest = e.client.RequestDelay()
+	}
+	est.Duration = perRequest * time.Duration(est.TotalRequests())
+
+	return est, nil
+}
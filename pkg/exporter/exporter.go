@@ -0,0 +1,1003 @@
+// Package exporter downloads trades from Tradervue into a data directory of
+// per-day JSON files, tracking progress so later runs are incremental.
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/atomicfile"
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/api"
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+const (
+	stateFile   = "state.json"
+	tradesDir   = dayfile.Dir
+	tvDateFmt   = "01/02/2006" // Tradervue API date format (mm/dd/yyyy)
+	fileDateFmt = "2006-01-02" // File naming format (yyyy-mm-dd)
+
+	defaultConcurrency = 4
+)
+
+// Options controls the export behavior.
+type Options struct {
+	WithExecutions bool
+	FromDate       string // yyyy-mm-dd override
+	ToDate         string // yyyy-mm-dd override
+	Force          bool
+	Concurrency    int      // parallel execution fetches (default 4)
+	Symbols        []string // only export these symbols; empty means all
+	Tags           []string // only export trades with any of these tags; empty means all
+	Verify         bool     // backfill missing day files instead of exporting new ones
+	DryRun         bool     // fetch and report, but write no files
+	Quiet          bool     // log only final summaries, warnings, and errors
+	Verbose        bool     // also log every page fetched and every trade's executions
+	Timezone       string   // IANA zone for grouping trades into days (default America/New_York)
+	WithSummary    bool     // also cache each day's computed summary for "tvue summary"
+	Compress       bool     // write day files gzipped, as trades/yyyy-mm-dd.json.gz
+	ClosedOnly     bool     // leave open trades out of the day files until they close
+	ReconcileFees  bool     // with WithExecutions, check each trade's fees against its executions
+	FeeTolerance   float64  // with ReconcileFees, differences up to this are ignored
+	NotesFile      string   // after exporting, write the notes of trades in range to this Markdown file
+	ChunkDays      int      // fetch, write, and save state this many days at a time; 0 means the whole range at once
+
+	// SynthesizeExecutions, with WithExecutions, stands in an entry and an
+	// exit execution for trades Tradervue returns no executions for.
+	SynthesizeExecutions bool
+
+	// FailFast, with WithExecutions, stops the export at the first trade
+	// whose executions fail to download. By default the trade is saved
+	// without them, reported as a problem, and the export carries on.
+	FailFast bool
+}
+
+// DefaultTimezone is the zone trades are grouped into days by when no
+// timezone is configured: US market hours.
+const DefaultTimezone = "America/New_York"
+
+// Exporter orchestrates the trade export from Tradervue.
+type Exporter struct {
+	client   *api.Client
+	dataDir  string
+	manifest *models.Manifest // loaded on first day write
+	loc      *time.Location   // zone trade dates are grouped in; set by useTimezone
+	report   *Report          // problems of the current Run
+	open     map[string]bool  // days written this Run -> whether they hold open trades
+	logger   logging.Logger   // progress and warnings; text on stderr by default
+	level    logging.Level    // progress detail of the current run, from Options
+	chunked  bool             // the current run exports its range in chunks
+	failedAt string           // first day of the current Run that couldn't be written
+
+	stop context.CancelCauseFunc // cancels the current Run with a cause
+}
+
+// New creates a new Exporter. It logs progress as text on stderr until
+// SetLogger says otherwise.
+func New(client *api.Client, dataDir string) *Exporter {
+	return &Exporter{client: client, dataDir: dataDir, logger: logging.NewText(os.Stderr)}
+}
+
+// SetLogger sends the exporter's progress and warnings to l. Use
+// logging.Discard to silence it.
+func (e *Exporter) SetLogger(l logging.Logger) {
+	if l == nil {
+		l = logging.NewText(os.Stderr)
+	}
+	e.logger = l
+}
+
+// filtered reports whether opts export only some of each day's trades.
+func (opts Options) filtered() bool {
+	return len(opts.Symbols) > 0 || len(opts.Tags) > 0
+}
+
+// listFilters are the Tradervue-side filters that fetch the trades opts
+// ask for: one listing per symbol and tag pair, or a single unfiltered
+// listing. UNKNOWN stands for trades without a symbol, which Tradervue
+// can't filter on, so it needs the unfiltered listing as well.
+func (opts Options) listFilters() []api.TradeFilter {
+	symbols := []string{""}
+	if len(opts.Symbols) > 0 {
+		symbols = nil
+		for _, s := range opts.Symbols {
+			s = strings.ToUpper(strings.TrimSpace(s))
+			if s == models.UnknownSymbol {
+				symbols = []string{""}
+				break
+			}
+			symbols = append(symbols, s)
+		}
+	}
+	tags := []string{""}
+	if len(opts.Tags) > 0 {
+		tags = opts.Tags
+	}
+
+	var filters []api.TradeFilter
+	for _, s := range symbols {
+		for _, t := range tags {
+			filters = append(filters, api.TradeFilter{Symbol: s, Tag: t})
+		}
+	}
+	return filters
+}
+
+// level is the logging.Level that Quiet and Verbose ask for.
+func (opts Options) level() logging.Level {
+	switch {
+	case opts.Quiet:
+		return logging.LevelQuiet
+	case opts.Verbose:
+		return logging.LevelVerbose
+	}
+	return logging.LevelNormal
+}
+
+// infof logs routine progress, which Quiet hides. Final summaries go
+// straight to e.logger so they are always shown.
+func (e *Exporter) infof(format string, args ...any) {
+	if e.level >= logging.LevelNormal {
+		e.logger.Infof(format, args...)
+	}
+}
+
+// donef logs the outcome of an export range. It is a final summary, so
+// always shown, unless the range is one chunk of a larger export that
+// summarizes itself at the end.
+func (e *Exporter) donef(format string, args ...any) {
+	if e.chunked {
+		e.infof(format, args...)
+		return
+	}
+	e.logger.Infof(format, args...)
+}
+
+// verbosef logs detail only shown with Verbose.
+func (e *Exporter) verbosef(format string, args ...any) {
+	if e.level >= logging.LevelVerbose {
+		e.logger.Infof(format, args...)
+	}
+}
+
+// Run executes the export process. If ctx is cancelled mid-export, days
+// already written are recorded in the state file before returning.
+//
+// The returned Report lists non-fatal problems, such as executions that
+// failed to download or day files that couldn't be written; the export
+// carries on past them. A non-nil error means the export itself failed.
+//
+// Except for a dry run, Run holds the data directory's lock file while it
+// works and fails with ErrLocked if another export holds it.
+func (e *Exporter) Run(ctx context.Context, opts Options) (*Report, error) {
+	if !opts.DryRun {
+		unlock, err := e.lock()
+		if err != nil {
+			return &Report{}, err
+		}
+		defer unlock()
+	}
+
+	e.report = &Report{}
+	e.open = make(map[string]bool)
+	e.failedAt = ""
+	defer func() { e.report, e.open = nil, nil }()
+
+	// An API that stops answering ends the run as Ctrl-C would, so the days
+	// already written are still recorded in the state file
+	ctx, e.stop = context.WithCancelCause(ctx)
+	defer func() { e.stop(nil); e.stop = nil }()
+
+	err := e.run(ctx, opts)
+	if err == nil && opts.NotesFile != "" && !opts.DryRun {
+		if err := e.writeNotes(opts); err != nil {
+			e.warn("", "writing notes: %v", err)
+		}
+	}
+	return e.report, err
+}
+
+func (e *Exporter) run(ctx context.Context, opts Options) error {
+	e.level = opts.level()
+	e.useTimezone(opts.Timezone)
+
+	// Ensure data directories exist
+	if !opts.DryRun {
+		tradesPath := filepath.Join(e.dataDir, tradesDir)
+		if err := os.MkdirAll(tradesPath, 0755); err != nil {
+			return fmt.Errorf("creating data directory: %w", err)
+		}
+	}
+
+	state, _ := e.loadState()
+	e.checkTimezone(state)
+
+	if opts.Verify {
+		return e.backfill(ctx, opts, state)
+	}
+
+	startDate, endDate, err := e.resolveRange(ctx, opts, state)
+	if err != nil {
+		return err
+	}
+
+	// Days with open trades get re-fetched until the trades close. A symbol
+	// or tag filter would save them incomplete, so they wait for a full
+	// export.
+	if state != nil && !opts.filtered() {
+		if err := e.revisitOpenDays(ctx, opts, state, startDate); err != nil {
+			return err
+		}
+	}
+
+	if startDate.After(endDate) {
+		e.logger.Infof("Already up to date. No new trades to export.")
+		return nil
+	}
+
+	chunks := splitRange(startDate, endDate, opts.ChunkDays)
+	if len(chunks) == 1 {
+		if err := e.exportRange(ctx, opts, state, startDate, endDate); err != nil {
+			return err
+		}
+		return e.recordRebuild(opts)
+	}
+
+	// Each chunk is fetched, written, and recorded in the state file before
+	// the next, so an interrupted backfill resumes after the last one done.
+	e.chunked = true
+	defer func() { e.chunked = false }()
+	e.infof("Exporting %s to %s in %d chunks of up to %d days", startDate.Format(fileDateFmt), endDate.Format(fileDateFmt), len(chunks), opts.ChunkDays)
+	for _, c := range chunks {
+		if err := e.exportRange(ctx, opts, state, c[0], c[1]); err != nil {
+			return err
+		}
+		state, _ = e.loadState()
+	}
+	if err := e.recordRebuild(opts); err != nil {
+		return err
+	}
+
+	switch {
+	case opts.DryRun:
+	case opts.filtered():
+		e.logger.Infof("Export complete: %d days, %d trades (filter active, state not updated)", len(e.report.Exported), e.report.Trades)
+	default:
+		e.logger.Infof("Export complete: %d days, %d trades", len(e.report.Exported), e.report.Trades)
+	}
+	return nil
+}
+
+// splitRange cuts start..end (inclusive) into consecutive ranges of at most
+// days days each. days <= 0 leaves it whole.
+func splitRange(start, end time.Time, days int) [][2]time.Time {
+	if days <= 0 {
+		return [][2]time.Time{{start, end}}
+	}
+	var chunks [][2]time.Time
+	for from := start; !from.After(end); from = from.AddDate(0, 0, days) {
+		to := from.AddDate(0, 0, days-1)
+		if to.After(end) {
+			to = end
+		}
+		chunks = append(chunks, [2]time.Time{from, to})
+	}
+	return chunks
+}
+
+// exportRange fetches the trades from startDate to endDate, writes their
+// day files, and advances state, which is nil before the first export.
+func (e *Exporter) exportRange(ctx context.Context, opts Options, state *models.ExportState, startDate, endDate time.Time) error {
+	e.infof("Exporting trades from %s to %s...", startDate.Format(fileDateFmt), endDate.Format(fileDateFmt))
+
+	// Fetch all trades in the date range, letting Tradervue apply the
+	// symbol and tag filters
+	allTrades, err := e.fetchFilteredTrades(ctx, startDate, endDate, opts.Quiet, opts.listFilters())
+	if err != nil {
+		return err
+	}
+
+	// Narrow client-side as well, for UNKNOWN and in case Tradervue
+	// matches more loosely (by case, say) than the summaries do. Blank
+	// symbols become UNKNOWN first, so --symbol UNKNOWN keeps them.
+	e.normalizeSymbols(allTrades)
+	if len(opts.Symbols) > 0 {
+		allTrades = models.FilterSymbols(allTrades, opts.Symbols)
+	}
+	allTrades = models.FilterTags(allTrades, opts.Tags, false)
+
+	// Group trades by date
+	byDate := e.groupTradesByDate(allTrades)
+	if opts.Force && !opts.filtered() && !opts.DryRun {
+		if err := e.clearMovedDays(startDate, endDate, byDate, opts); err != nil {
+			return err
+		}
+	}
+
+	if len(allTrades) == 0 {
+		e.donef("No trades found in the date range.")
+		return nil
+	}
+	if opts.ClosedOnly {
+		if n := e.dropOpenTrades(byDate); n > 0 {
+			e.infof("Leaving out %d open trades (--closed-only); they are fetched again until they close", n)
+		}
+		if len(byDate) == 0 {
+			// State isn't advanced, so the next run fetches this range again
+			e.donef("No closed trades in the date range.")
+			return nil
+		}
+	}
+	dates := sortedKeys(byDate)
+
+	// Days a full export already wrote (say, in an interrupted run whose
+	// state was never saved) are kept as they are unless --force. Partial
+	// files, and ones the manifest doesn't know, are fetched again and
+	// merged.
+	var existing map[string]bool
+	if !opts.Force {
+		existing, _ = e.completeDates()
+		if state != nil && !opts.filtered() {
+			for _, d := range state.OpenTradeDates {
+				delete(existing, d)
+			}
+		}
+	}
+
+	if opts.DryRun {
+		e.reportDryRun(byDate, dates, existing, opts.Compress)
+		return nil
+	}
+
+	prevLast := ""
+	if state != nil {
+		prevLast = state.LastExportDate
+	}
+
+	totalTrades := 0
+	newTrades := 0 // trades on days after the state's last export, not yet counted
+	var saved, skipped []string
+	for _, date := range dates {
+		if ctx.Err() != nil {
+			break
+		}
+		trades := byDate[date]
+
+		if existing[date] {
+			saved = append(saved, date)
+			skipped = append(skipped, date)
+			if date > prevLast {
+				newTrades += len(trades)
+			}
+			continue
+		}
+
+		if err := e.exportDay(ctx, date, trades, opts); err != nil {
+			if ctx.Err() != nil {
+				// Don't persist a day whose executions were cut short.
+				break
+			}
+			e.warn(date, "%v", err)
+			if e.failedAt == "" {
+				e.failedAt = date
+			}
+			continue
+		}
+		saved = append(saved, date)
+		totalTrades += len(trades)
+		if date > prevLast {
+			newTrades += len(trades)
+		}
+	}
+
+	if len(skipped) > 0 {
+		e.logger.Infof("Skipped %d days already on disk (use --force to re-export them)", len(skipped))
+	}
+
+	if len(saved) == 0 {
+		if ctx.Err() != nil {
+			return fmt.Errorf("export interrupted: %w", context.Cause(ctx))
+		}
+		return fmt.Errorf("no day files could be written")
+	}
+
+	// A filtered export only holds some of each day's trades, so don't let it
+	// advance the incremental state past days that still need a full export.
+	if opts.filtered() {
+		if ctx.Err() != nil {
+			return fmt.Errorf("export interrupted: %w", context.Cause(ctx))
+		}
+		e.donef("Export complete: %d days, %d trades (filter active, state not updated)", len(saved)-len(skipped), totalTrades)
+		return nil
+	}
+
+	// Update state
+	if state == nil {
+		state = &models.ExportState{}
+	}
+	if state.FirstTradeDate == "" || saved[0] < state.FirstTradeDate {
+		state.FirstTradeDate = saved[0]
+	}
+	// Don't advance past a failed day, so the next run retries it (days
+	// after it are on disk and will be skipped). The day may have failed
+	// in an earlier chunk of this run.
+	lastDate := ""
+	for _, d := range saved {
+		if e.failedAt == "" || d < e.failedAt {
+			lastDate = d
+		}
+	}
+	if lastDate > state.LastExportDate {
+		state.LastExportDate = lastDate
+	}
+	state.TotalTrades += newTrades
+	state.TotalDays += countAfter(saved, prevLast)
+	state.LastRunAt = time.Now()
+	e.recordOpenDays(state)
+	e.recordSettings(state, opts)
+
+	if err := e.saveState(state); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+
+	if ctx.Err() != nil {
+		e.logger.Infof("Export interrupted after %d of %d days (%d trades saved)", len(saved), len(dates), totalTrades)
+		return fmt.Errorf("export interrupted: %w", context.Cause(ctx))
+	}
+
+	e.donef("Export complete: %d days, %d trades", len(saved)-len(skipped), totalTrades)
+	return nil
+}
+
+// countAfter counts the dates later than after, i.e. days new to the state.
+func countAfter(dates []string, after string) int {
+	n := 0
+	for _, d := range dates {
+		if d > after {
+			n++
+		}
+	}
+	return n
+}
+
+// reportDryRun logs the day files an export would write, and those it
+// would skip because they already exist.
+func (e *Exporter) reportDryRun(byDate map[string][]models.Trade, dates []string, existing map[string]bool, compress bool) {
+	total, days := 0, 0
+	for _, date := range dates {
+		trades := byDate[date]
+		path := filepath.Join(tradesDir, dayfile.Name(date, compress))
+		if existing[date] {
+			e.logger.Infof("  would skip %s: already exists", path)
+			continue
+		}
+		total += len(trades)
+		days++
+		e.logger.Infof("  would write %s: %d trades [%s]", path, len(trades), summarizeSymbols(trades))
+	}
+	e.logger.Infof("Dry run: %d days, %d trades would be exported. Nothing was written.", days, total)
+}
+
+// exportDay writes one day file, fetching executions first when requested.
+// Trades already saved for the date are merged in by ID, see mergeExisting.
+func (e *Exporter) exportDay(ctx context.Context, date string, trades []models.Trade, opts Options) error {
+	dayExport := &models.DayExport{
+		Date:       date,
+		Trades:     trades,
+		ExportedAt: time.Now(),
+		Partial:    opts.filtered(),
+	}
+
+	// Optionally fetch executions
+	if opts.WithExecutions {
+		execs, failed, err := e.fetchExecutionsForTrades(ctx, date, trades, opts.Concurrency, opts.FailFast)
+		if err != nil {
+			return err
+		}
+		if len(failed) > 0 && e.report != nil {
+			e.report.addFailedExecutions(failed)
+		}
+		dayExport.Executions = execs
+		if opts.ReconcileFees {
+			e.reconcileFees(date, trades, execs, opts.FeeTolerance)
+		}
+	}
+
+	merge := e.mergeExisting(dayExport, opts)
+	if opts.ClosedOnly {
+		// Open trades saved by an earlier full export are dropped too. The
+		// fetched ones are closed already, so any dropped here were kept.
+		dropped := len(dayExport.Trades)
+		dayExport.Trades = closedTrades(dayExport.Trades)
+		dropped -= len(dayExport.Trades)
+		merge.kept -= dropped
+		merge.removed += dropped
+		for id := range dayExport.Executions {
+			if !hasTrade(dayExport.Trades, id) {
+				delete(dayExport.Executions, id)
+			}
+		}
+	}
+
+	if opts.WithExecutions && opts.SynthesizeExecutions {
+		if n := synthesizeExecutions(dayExport); n > 0 {
+			e.infof("  %s: synthesized entry and exit executions for %d trades", date, n)
+		}
+	}
+
+	if err := e.saveDayExport(dayExport, opts.Compress); err != nil {
+		return fmt.Errorf("saving %s: %w", date, err)
+	}
+	if err := e.updateSummaryCache(date, dayExport.Trades, opts.WithSummary); err != nil {
+		return fmt.Errorf("saving summary for %s: %w", date, err)
+	}
+
+	if e.report != nil {
+		e.report.addExport(date, len(dayExport.Trades))
+	}
+	e.noteOpenTrades(date, dayExport.Trades)
+
+	// Build symbol summary for log
+	symbols := summarizeSymbols(dayExport.Trades)
+	if dayExport.Partial {
+		e.infof("  %s: %d trades [%s] (partial: only the filtered trades)", date, len(dayExport.Trades), symbols)
+	} else if merge.existed {
+		e.infof("  %s: %d trades [%s] (%s)", date, len(dayExport.Trades), symbols, merge)
+	} else {
+		e.infof("  %s: %d trades [%s]", date, len(dayExport.Trades), symbols)
+	}
+	return nil
+}
+
+// resolveRange determines the date range to export from the options and the
+// saved state, discovering the first trade date on a first run.
+func (e *Exporter) resolveRange(ctx context.Context, opts Options, state *models.ExportState) (time.Time, time.Time, error) {
+	var startDate, endDate time.Time
+
+	// Validate explicit dates before any (potentially slow) discovery
+	endDate = time.Now()
+	if opts.ToDate != "" {
+		to, err := time.Parse(fileDateFmt, opts.ToDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --to date %q (use yyyy-mm-dd): %w", opts.ToDate, err)
+		}
+		if today := endDate.Format(fileDateFmt); opts.ToDate > today {
+			e.logger.Warnf("--to %s is in the future; exporting up to today (%s)", opts.ToDate, today)
+		} else {
+			endDate = to
+		}
+	}
+
+	if opts.FromDate != "" {
+		var err error
+		startDate, err = time.Parse(fileDateFmt, opts.FromDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from date %q (use yyyy-mm-dd): %w", opts.FromDate, err)
+		}
+		if opts.FromDate > endDate.Format(fileDateFmt) {
+			if opts.ToDate != "" && opts.FromDate > opts.ToDate {
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid date range: --from %s is after --to %s", opts.FromDate, opts.ToDate)
+			}
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from date %s: it is in the future", opts.FromDate)
+		}
+	} else if state != nil && state.LastExportDate != "" && !opts.Force {
+		last, err := time.Parse(fileDateFmt, state.LastExportDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("corrupt state file: %w", err)
+		}
+		startDate = last.AddDate(0, 0, 1) // day after last export
+	} else if state != nil && state.FirstTradeDate != "" {
+		// --force: the first trade date is already known, no need to rediscover it
+		first, err := time.Parse(fileDateFmt, state.FirstTradeDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("corrupt state file: %w", err)
+		}
+		startDate = first
+	} else {
+		// First run: discover first trade date
+		e.infof("First run: discovering first trade date...")
+		first, err := e.discoverFirstTradeDate(ctx, opts.Quiet)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		startDate = first
+		e.infof("First trade found on: %s", startDate.Format(fileDateFmt))
+	}
+
+	return startDate, endDate, nil
+}
+
+// fetchAllTrades retrieves all trades in a date range with pagination.
+// Trades entered while the pages are read shift the rest down, so a trade
+// can turn up again on the next page; only its first appearance is kept.
+func (e *Exporter) fetchAllTrades(ctx context.Context, start, end time.Time, quiet bool) ([]models.Trade, error) {
+	return e.fetchFilteredTrades(ctx, start, end, quiet, []api.TradeFilter{{}})
+}
+
+// fetchFilteredTrades is fetchAllTrades with one listing per filter, each
+// trade kept once even if several listings return it.
+func (e *Exporter) fetchFilteredTrades(ctx context.Context, start, end time.Time, quiet bool, filters []api.TradeFilter) ([]models.Trade, error) {
+	startStr := start.Format(tvDateFmt)
+	endStr := end.Format(tvDateFmt)
+
+	var all []models.Trade
+	kept := make(map[int]bool)
+	dupes := 0
+
+	prog := e.newProgress("Fetching", quiet)
+	defer prog.finish()
+
+	for _, filter := range filters {
+		seen := make(map[int]bool) // in this listing
+		for page := 1; ; page++ {
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("fetching trades page %d: %w", page, err)
+			}
+			trades, err := e.client.ListTradesFiltered(ctx, startStr, endStr, page, filter)
+			if err != nil {
+				return nil, fmt.Errorf("fetching trades page %d: %w", page, err)
+			}
+			if len(trades) == 0 {
+				break
+			}
+			for _, t := range trades {
+				if seen[t.ID] {
+					dupes++
+					continue
+				}
+				seen[t.ID] = true
+				if !kept[t.ID] {
+					kept[t.ID] = true
+					all = append(all, t)
+				}
+			}
+
+			// Pages run newest first, so the oldest trade so far tells how
+			// far back through the range we are
+			done := -1.0
+			if oldest, err := e.parseTradeDate(trades[len(trades)-1].StartDatetime); err == nil {
+				done = rangeDone(start, end, oldest)
+			}
+			prog.page(len(trades), done)
+
+			if len(trades) < 100 {
+				break
+			}
+		}
+	}
+
+	if dupes > 0 {
+		e.infof("Dropped %d trades listed twice (on two pages, as trades entered during the export shifted them)", dupes)
+	}
+	return all, nil
+}
+
+// normalizeSymbols records trades without a symbol as UNKNOWN, warning
+// about each. Trades already normalized pass through silently.
+func (e *Exporter) normalizeSymbols(trades []models.Trade) {
+	for _, id := range models.NormalizeSymbols(trades) {
+		e.logger.Warnf("trade %d has no symbol, recording it as %s", id, models.UnknownSymbol)
+	}
+}
+
+// groupTradesByDate groups trades by the date portion of their
+// StartDatetime, normalizing their symbols first.
+func (e *Exporter) groupTradesByDate(trades []models.Trade) map[string][]models.Trade {
+	byDate := make(map[string][]models.Trade)
+
+	e.normalizeSymbols(trades)
+
+	for _, t := range trades {
+		date, err := e.parseTradeDate(t.StartDatetime)
+		if err != nil {
+			e.warn("", "skipping trade %d with unparseable date %q", t.ID, t.StartDatetime)
+			continue
+		}
+		key := date.Format(fileDateFmt)
+		byDate[key] = append(byDate[key], t)
+	}
+
+	return byDate
+}
+
+// fetchExecutionsForTrades fetches executions for each trade using a pool of
+// workers. The client's rate limiter is shared, so requests stay spaced out
+// regardless of the worker count. Each trade is fetched on its own: a
+// failure is reported as a problem on date and that trade is left out of
+// the map and listed in the returned IDs, while a trade Tradervue no
+// longer has (HTTP 404) is only warned about. With failFast, the first
+// failure stops the run instead. Only cancellation of ctx is returned as
+// an error.
+func (e *Exporter) fetchExecutionsForTrades(ctx context.Context, date string, trades []models.Trade, workers int, failFast bool) (map[int][]models.Execution, []int, error) {
+	if workers < 1 {
+		workers = defaultConcurrency
+	}
+
+	// Each worker writes only to its own trade's slot, so no locking is needed.
+	fetched := make([][]models.Execution, len(trades))
+	failed := make([]bool, len(trades))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				execs, err := e.client.GetExecutions(ctx, trades[i].ID)
+				if errors.Is(err, api.ErrNotFound) {
+					// Deleted since it was listed; nothing is missing from the day
+					e.logger.Warnf("%s: trade %d (%s) was not found on Tradervue (HTTP 404); skipping its executions",
+						date, trades[i].ID, trades[i].Symbol)
+					continue
+				}
+				if errors.Is(err, api.ErrAPIDown) {
+					// Stop the whole run; this day isn't saved incomplete
+					e.stop(err)
+					continue
+				}
+				if err != nil {
+					if ctx.Err() != nil {
+						continue
+					}
+					if failFast {
+						e.stop(fmt.Errorf("--fail-fast: fetching executions for trade %d on %s: %w", trades[i].ID, date, err))
+						continue
+					}
+					e.warn(date, "failed to fetch executions for trade %d: %v", trades[i].ID, err)
+					failed[i] = true
+					continue
+				}
+				fetched[i] = execs
+				e.verbosef("    %s: trade %d (%s): %d executions", date, trades[i].ID, trades[i].Symbol, len(execs))
+				e.noteExecutionGap(date, trades[i], len(execs))
+			}
+		}()
+	}
+
+	for i := range trades {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	result := make(map[int][]models.Execution)
+	var failedIDs []int
+	for i, t := range trades {
+		if len(fetched[i]) > 0 {
+			result[t.ID] = fetched[i]
+		}
+		if failed[i] {
+			failedIDs = append(failedIDs, t.ID)
+		}
+	}
+
+	return result, failedIDs, nil
+}
+
+// saveDayExport writes a day's export to a JSON file, gzipped when compress
+// is set. The write is atomic, so a crash leaves either the previous file or
+// the new one, never a mix. A file for the same day in the other form is
+// removed afterwards, so there is only ever one copy.
+func (e *Exporter) saveDayExport(day *models.DayExport, compress bool) error {
+	path := dayfile.Path(e.dataDir, day.Date, compress)
+
+	data, err := dayfile.Encode(day, compress)
+	if err != nil {
+		return err
+	}
+
+	if err := atomicfile.Write(path, data, 0644); err != nil {
+		return err
+	}
+	if err := dayfile.RemoveOther(e.dataDir, day.Date, compress); err != nil {
+		return err
+	}
+
+	return e.recordManifest(day, data)
+}
+
+// updateSummaryCache writes the day's computed summary when write is set.
+// Otherwise it removes any cached summary, which the new day file has made
+// stale.
+func (e *Exporter) updateSummaryCache(date string, trades []models.Trade, write bool) error {
+	path := summary.CachePath(e.dataDir, date)
+	if !write {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := summary.MarshalCache(summary.Summarize(date, trades))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return atomicfile.Write(path, data, 0644)
+}
+
+// loadDayExport reads an existing day file in whichever form it was saved,
+// reporting whether it is compressed.
+func (e *Exporter) loadDayExport(date string) (*models.DayExport, bool, error) {
+	f, err := dayfile.Find(e.dataDir, date)
+	if err != nil {
+		return nil, false, err
+	}
+
+	day, err := dayfile.Load(f.Path)
+	if err != nil {
+		return nil, false, err
+	}
+	return day, f.Compressed, nil
+}
+
+// loadState reads the export state file.
+func (e *Exporter) loadState() (*models.ExportState, error) {
+	path := filepath.Join(e.dataDir, stateFile)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var state models.ExportState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// saveState writes the export state file.
+func (e *Exporter) saveState(state *models.ExportState) error {
+	path := filepath.Join(e.dataDir, stateFile)
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(path, data, 0644)
+}
+
+// useTimezone sets the zone trade dates are grouped in. A zone that fails
+// to load falls back to UTC with a warning, rather than silently keeping
+// each trade's original offset.
+func (e *Exporter) useTimezone(name string) {
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		e.logger.Warnf("cannot load timezone %q (%v); grouping trades by UTC date", name, err)
+		loc = time.UTC
+	}
+	e.loc = loc
+}
+
+// checkTimezone warns when earlier exports grouped days in a different zone
+// than this run, since the day files would then disagree on where each day
+// starts.
+func (e *Exporter) checkTimezone(state *models.ExportState) {
+	if state == nil || state.Timezone == "" || state.Timezone == e.loc.String() {
+		return
+	}
+	e.logger.Warnf("earlier exports grouped trades into days by %s, but this run uses %s; "+
+		"re-export with --force (without --from, --to, --symbol, or --tag) to regroup every day", state.Timezone, e.loc)
+}
+
+// recordSettings stores the options that shape the day files in the state,
+// so later runs and "tvue summary" can tell when they change. The timezone
+// is only set when the state has none: one run in another zone doesn't
+// regroup the days already on disk, so only recordRebuild replaces it.
+func (e *Exporter) recordSettings(state *models.ExportState, opts Options) {
+	if state.Timezone == "" {
+		state.Timezone = e.loc.String()
+	}
+	if opts.WithExecutions {
+		state.WithExecutions = true
+	}
+}
+
+// recordRebuild stores this run's settings in the state after a --force
+// re-export of the whole history that finished without problems, since it
+// rewrote every day file under them.
+func (e *Exporter) recordRebuild(opts Options) error {
+	if !opts.Force || opts.filtered() || opts.DryRun || opts.FromDate != "" || opts.ToDate != "" || !e.report.OK() {
+		return nil
+	}
+	state, err := e.loadState()
+	if err != nil || state == nil {
+		return err
+	}
+	if state.Timezone != e.loc.String() {
+		e.logger.Infof("Every day is now grouped by %s", e.loc)
+	}
+	state.Timezone = e.loc.String()
+	state.WithExecutions = opts.WithExecutions
+	if err := e.saveState(state); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	return nil
+}
+
+// parseTradeDate extracts a time.Time from a Tradervue datetime string.
+// Tradervue returns ISO 8601 format like "2025-01-15T09:30:00-05:00".
+func (e *Exporter) parseTradeDate(datetime string) (time.Time, error) {
+	// Try full ISO 8601
+	t, err := time.Parse(time.RFC3339, datetime)
+	if err == nil {
+		// Convert to the configured timezone for consistent date grouping
+		if e.loc == nil {
+			e.useTimezone("")
+		}
+		return t.In(e.loc), nil
+	}
+
+	// Try date-only format
+	t, err = time.Parse(fileDateFmt, datetime)
+	if err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("cannot parse date %q", datetime)
+}
+
+// summarizeSymbols creates a compact string like "SNGX(L) MULN(S)"
+func summarizeSymbols(trades []models.Trade) string {
+	seen := make(map[string]string) // symbol -> side
+	var order []string
+
+	for _, t := range trades {
+		if _, ok := seen[t.Symbol]; !ok {
+			order = append(order, t.Symbol)
+		}
+		existing := seen[t.Symbol]
+		if existing == "" {
+			seen[t.Symbol] = t.Side
+		} else if existing != t.Side {
+			seen[t.Symbol] = "L/S"
+		}
+	}
+
+	var parts []string
+	for _, sym := range order {
+		if seen[sym] == "" {
+			parts = append(parts, sym)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s(%s)", sym, seen[sym]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// sortedKeys returns map keys sorted alphabetically.
+func sortedKeys(m map[string][]models.Trade) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,217 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/api"
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// fakeTradervue answers trade listings from a fixed set of trades, applying
+// the date and symbol filters as Tradervue does, and returns no executions
+// or journal entries.
+type fakeTradervue struct {
+	trades []models.Trade // newest first, as Tradervue lists them
+}
+
+func (f fakeTradervue) RoundTrip(r *http.Request) (*http.Response, error) {
+	body := `{"executions": [], "journal_entries": []}`
+	if strings.HasSuffix(r.URL.Path, "/trades") {
+		q := r.URL.Query()
+		from := tvToFileDate(q.Get("startdate"))
+		to := tvToFileDate(q.Get("enddate"))
+		var page []models.Trade
+		if q.Get("page") == "1" {
+			for _, t := range f.trades {
+				date := t.StartDatetime[:10]
+				if (from != "" && date < from) || (to != "" && date > to) {
+					continue
+				}
+				if sym := q.Get("symbol"); sym != "" && !strings.EqualFold(sym, t.Symbol) {
+					continue
+				}
+				page = append(page, t)
+			}
+		}
+		data, err := json.Marshal(map[string][]models.Trade{"trades": page})
+		if err != nil {
+			return nil, err
+		}
+		body = string(data)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     make(http.Header),
+		Request:    r,
+	}, nil
+}
+
+// tvToFileDate turns a Tradervue mm/dd/yyyy date into yyyy-mm-dd.
+func tvToFileDate(s string) string {
+	d, err := time.Parse(tvDateFmt, s)
+	if err != nil {
+		return ""
+	}
+	return d.Format(fileDateFmt)
+}
+
+// newFakeExporter returns a quiet Exporter for dir whose client talks to a
+// fakeTradervue serving trades.
+func newFakeExporter(dir string, trades []models.Trade) *Exporter {
+	client := api.NewClient(api.BasicAuth{}, "test",
+		api.WithTransport(fakeTradervue{trades: trades}),
+		api.WithRequestDelay(0))
+	e := New(client, dir)
+	e.SetLogger(logging.Discard)
+	return e
+}
+
+func TestResolveRange(t *testing.T) {
+	today := time.Now().Format(fileDateFmt)
+	future := time.Now().AddDate(0, 1, 0).Format(fileDateFmt)
+
+	tests := []struct {
+		name     string
+		from, to string
+		wantFrom string
+		wantTo   string
+		wantErr  string // substring; "" for success
+		wantWarn string // substring of the log; "" for none
+	}{
+		{name: "explicit range", from: "2025-01-02", to: "2025-01-31", wantFrom: "2025-01-02", wantTo: "2025-01-31"},
+		{name: "single day", from: "2025-01-02", to: "2025-01-02", wantFrom: "2025-01-02", wantTo: "2025-01-02"},
+		{name: "from only runs to today", from: "2025-01-02", wantFrom: "2025-01-02", wantTo: today},
+		{name: "inverted range", from: "2025-06-01", to: "2025-01-01", wantErr: "--from 2025-06-01 is after --to 2025-01-01"},
+		{name: "malformed from", from: "2025-13-01", to: "2025-01-31", wantErr: `invalid --from date "2025-13-01"`},
+		{name: "from not yyyy-mm-dd", from: "01/02/2025", wantErr: `invalid --from date "01/02/2025"`},
+		{name: "malformed to", from: "2025-01-02", to: "2025-02-30", wantErr: `invalid --to date "2025-02-30"`},
+		{name: "future from", from: future, wantErr: "it is in the future"},
+		{name: "future from after to", from: future, to: "2025-01-01", wantErr: "is after --to 2025-01-01"},
+		{name: "future to clamps to today", from: "2025-01-02", to: future, wantFrom: "2025-01-02", wantTo: today, wantWarn: "is in the future; exporting up to today"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log bytes.Buffer
+			e := &Exporter{logger: logging.NewText(&log)}
+			start, end, err := e.resolveRange(context.Background(), Options{FromDate: tt.from, ToDate: tt.to}, nil)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveRange(%q, %q) error = %v, want one containing %q", tt.from, tt.to, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveRange(%q, %q): %v", tt.from, tt.to, err)
+			}
+			if got := start.Format(fileDateFmt); got != tt.wantFrom {
+				t.Errorf("start = %s, want %s", got, tt.wantFrom)
+			}
+			if got := end.Format(fileDateFmt); got != tt.wantTo {
+				t.Errorf("end = %s, want %s", got, tt.wantTo)
+			}
+			if tt.wantWarn == "" && log.Len() > 0 {
+				t.Errorf("unexpected log output: %s", log.String())
+			}
+			if tt.wantWarn != "" && !strings.Contains(log.String(), tt.wantWarn) {
+				t.Errorf("log = %q, want a warning containing %q", log.String(), tt.wantWarn)
+			}
+		})
+	}
+}
+
+func TestRecordSettingsKeepsTimezone(t *testing.T) {
+	e := &Exporter{logger: logging.Discard}
+	e.useTimezone("Europe/London")
+
+	state := &models.ExportState{}
+	e.recordSettings(state, Options{})
+	if state.Timezone != "Europe/London" {
+		t.Fatalf("first export recorded timezone %q, want Europe/London", state.Timezone)
+	}
+
+	e.useTimezone("Asia/Tokyo")
+	e.recordSettings(state, Options{WithExecutions: true})
+	if state.Timezone != "Europe/London" {
+		t.Errorf("a later run in another zone changed the timezone to %q", state.Timezone)
+	}
+	if !state.WithExecutions {
+		t.Error("an export with executions didn't record it")
+	}
+	e.recordSettings(state, Options{})
+	if !state.WithExecutions {
+		t.Error("an export without executions cleared WithExecutions")
+	}
+}
+
+func TestExportUnknownSymbol(t *testing.T) {
+	dir := t.TempDir()
+	e := newFakeExporter(dir, []models.Trade{
+		{ID: 2, Symbol: "AAPL", Side: "L", StartDatetime: "2025-01-02T11:00:00-05:00"},
+		{ID: 1, Symbol: "", Side: "L", StartDatetime: "2025-01-02T10:00:00-05:00"},
+	})
+
+	opts := Options{FromDate: "2025-01-02", ToDate: "2025-01-02", Symbols: []string{"UNKNOWN"}, Quiet: true}
+	if _, err := e.Run(context.Background(), opts); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	day, _, err := e.loadDayExport("2025-01-02")
+	if err != nil {
+		t.Fatalf("no day file written: %v", err)
+	}
+	if len(day.Trades) != 1 || day.Trades[0].ID != 1 || day.Trades[0].Symbol != models.UnknownSymbol {
+		t.Errorf("day trades = %+v, want only trade 1 as %s", day.Trades, models.UnknownSymbol)
+	}
+}
+
+func TestChunkedExportHoldsStateAtFailedDay(t *testing.T) {
+	dir := t.TempDir()
+	e := newFakeExporter(dir, []models.Trade{
+		{ID: 3, Symbol: "NVDA", Side: "L", StartDatetime: "2025-01-04T10:00:00-05:00"},
+		{ID: 2, Symbol: "TSLA", Side: "L", StartDatetime: "2025-01-03T10:00:00-05:00"},
+		{ID: 1, Symbol: "AAPL", Side: "L", StartDatetime: "2025-01-02T10:00:00-05:00"},
+	})
+
+	// A directory where the first day's file goes makes writing it fail;
+	// the rest of its chunk and the next chunk are written
+
+	blocked := dayfile.Path(dir, "2025-01-02", false)
+	if err := os.MkdirAll(filepath.Join(blocked, "x"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{FromDate: "2025-01-02", ToDate: "2025-01-04", ChunkDays: 2, Quiet: true}
+	report, err := e.Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.OK() {
+		t.Fatal("Run reported no problems, but the first day couldn't be written")
+	}
+	for _, date := range []string{"2025-01-03", "2025-01-04"} {
+		if _, _, err := e.loadDayExport(date); err != nil {
+			t.Errorf("%s not written: %v", date, err)
+		}
+	}
+
+	state, err := e.loadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.LastExportDate >= "2025-01-02" {
+		t.Errorf("LastExportDate = %s, want it before the failed day 2025-01-02", state.LastExportDate)
+	}
+}
@@ -0,0 +1,53 @@
+package exporter
+
+import (
+	"math"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// DefaultFeeTolerance is how far a trade's commission and fees may drift
+// from its executions' before --reconcile-fees reports it: a cent, to
+// absorb rounding.
+const DefaultFeeTolerance = 0.01
+
+// FeeMismatch is a trade whose commission and fees disagree with the sum
+// over its executions.
+type FeeMismatch struct {
+	Date       string
+	TradeID    int
+	Symbol     string
+	Trade      float64 // the trade's Commission + Fees
+	Executions float64 // Commission + TransFee + ECNFee summed over its executions
+}
+
+// Diff is how much the trade-level figure exceeds the executions' sum.
+func (m FeeMismatch) Diff() float64 {
+	return m.Trade - m.Executions
+}
+
+// reconcileFees compares each trade's commission and fees with its
+// executions and logs and reports the trades off by more than tolerance.
+// Trades without executions are skipped.
+func (e *Exporter) reconcileFees(date string, trades []models.Trade, execs map[int][]models.Execution, tolerance float64) {
+	for _, t := range trades {
+		ex, ok := execs[t.ID]
+		if !ok {
+			continue
+		}
+		m := FeeMismatch{Date: date, TradeID: t.ID, Symbol: t.Symbol, Trade: t.Commission + t.Fees}
+		for _, x := range ex {
+			m.Executions += x.Commission + x.TransFee + x.ECNFee
+		}
+		// Allow for float noise, so a tolerance of 0.01 means a whole cent
+		if math.Abs(m.Diff()) <= tolerance+1e-9 {
+			continue
+		}
+
+		e.logger.Warnf("%s: trade %d (%s) has commission+fees %.2f, but its executions add up to %.2f (%+.2f)",
+			date, t.ID, t.Symbol, m.Trade, m.Executions, m.Diff())
+		if e.report != nil {
+			e.report.addFeeMismatch(m)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package exporter
+
+import (
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// ExecutionGap is a trade Tradervue returned no executions for, although
+// its ExecCount says it has some. Older trades are prone to this.
+type ExecutionGap struct {
+	Date      string
+	TradeID   int
+	Symbol    string
+	ExecCount int
+}
+
+// noteExecutionGap logs and reports t when its executions came back empty
+// but it should have some.
+func (e *Exporter) noteExecutionGap(date string, t models.Trade, fetched int) {
+	if fetched > 0 || t.ExecCount == 0 {
+		return
+	}
+	e.logger.Warnf("%s: trade %d (%s) has %d executions on Tradervue, but none were returned",
+		date, t.ID, t.Symbol, t.ExecCount)
+	if e.report != nil {
+		e.report.addExecutionGap(ExecutionGap{Date: date, TradeID: t.ID, Symbol: t.Symbol, ExecCount: t.ExecCount})
+	}
+}
+
+// synthesizeExecutions fills in an entry and an exit execution for each of
+// the day's trades that should have executions but has none, or only ones
+// synthesized earlier, from the trade's entry and exit prices, volume, and
+// side. Open trades get only the entry. The trade's commission and fees go
+// on the last execution, so they still add up. It returns how many trades
+// were filled in.
+func synthesizeExecutions(day *models.DayExport) int {
+	n := 0
+	for _, t := range day.Trades {
+		if t.ExecCount == 0 || !allSynthetic(day.Executions[t.ID]) {
+			continue
+		}
+		if day.Executions == nil {
+			day.Executions = make(map[int][]models.Execution)
+		}
+		day.Executions[t.ID] = pseudoExecutions(t)
+		n++
+	}
+	return n
+}
+
+// allSynthetic reports whether execs has no real executions, which
+// includes having none at all.
+func allSynthetic(execs []models.Execution) bool {
+	for _, x := range execs {
+		if !x.Synthetic {
+			return false
+		}
+	}
+	return true
+}
+
+// pseudoExecutions stands in for a trade's fills: a buy and a sell of its
+// volume (the other way round for shorts) at its entry and exit prices,
+// with IDs from models.SyntheticExecutionID.
+func pseudoExecutions(t models.Trade) []models.Execution {
+	qty := t.Volume
+	if t.Side == "S" {
+		qty = -qty
+	}
+
+	execs := []models.Execution{{
+		ID:        models.SyntheticExecutionID(t.ID, 0),
+		Datetime:  t.StartDatetime,
+		Symbol:    t.Symbol,
+		Quantity:  qty,
+		Price:     t.EntryPrice,
+		Synthetic: true,
+	}}
+	if t.ExitPrice != nil {
+		exit := models.Execution{
+			ID:        models.SyntheticExecutionID(t.ID, 1),
+			Symbol:    t.Symbol,
+			Quantity:  -qty,
+			Price:     *t.ExitPrice,
+			Synthetic: true,
+		}
+		if t.EndDatetime != nil {
+			exit.Datetime = *t.EndDatetime
+		}
+		execs = append(execs, exit)
+	}
+
+	last := &execs[len(execs)-1]
+	last.Commission, last.TransFee = t.Commission, t.Fees
+	return execs
+}
@@ -0,0 +1,42 @@
+package exporter
+
+import (
+	"testing"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+func TestSynthesizedExecutionIDs(t *testing.T) {
+	exit := 101.0
+	day := &models.DayExport{Trades: []models.Trade{
+		{ID: 1, Symbol: "AAPL", Side: "L", Volume: 100, EntryPrice: 100, ExitPrice: &exit, ExecCount: 2},
+		{ID: 2, Symbol: "TSLA", Side: "S", Volume: 50, EntryPrice: 200, ExitPrice: &exit, ExecCount: 3},
+	}}
+	if n := synthesizeExecutions(day); n != 2 {
+		t.Fatalf("synthesizeExecutions = %d, want 2", n)
+	}
+
+	seen := map[int]bool{}
+	for _, tr := range day.Trades {
+		for _, x := range day.Executions[tr.ID] {
+			if x.ID >= 0 {
+				t.Errorf("trade %d: synthetic execution ID %d, want negative", tr.ID, x.ID)
+			}
+			if seen[x.ID] {
+				t.Errorf("trade %d: synthetic execution ID %d used twice", tr.ID, x.ID)
+			}
+			seen[x.ID] = true
+		}
+	}
+
+	// Synthesizing again gives the same IDs
+	again := &models.DayExport{Trades: day.Trades}
+	synthesizeExecutions(again)
+	for id, execs := range day.Executions {
+		for i, x := range execs {
+			if got := again.Executions[id][i].ID; got != x.ID {
+				t.Errorf("trade %d fill %d: ID %d on a second run, want %d", id, i, got, x.ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// RunJournal fetches daily journal entries and stores each one in the
+// Journal field of its day file. Existing trades in a day file are kept;
+// days with a journal entry but no trades get a journal-only file. Days that
+// already have a journal are skipped unless opts.Force is set. Like Run,
+// it holds the data directory's lock file while it works.
+func (e *Exporter) RunJournal(ctx context.Context, opts Options) error {
+	unlock, err := e.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	e.level = opts.level()
+	e.useTimezone(opts.Timezone)
+
+	tradesPath := filepath.Join(e.dataDir, tradesDir)
+	if err := os.MkdirAll(tradesPath, 0755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+
+	startDate, endDate, err := e.resolveJournalRange(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	e.infof("Exporting journal from %s to %s...", startDate.Format(fileDateFmt), endDate.Format(fileDateFmt))
+
+	entries, err := e.fetchAllJournal(ctx, startDate, endDate)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		e.logger.Infof("No journal entries found in the date range.")
+		return nil
+	}
+
+	written, skipped := 0, 0
+	for i := range entries {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("journal export interrupted: %w", err)
+		}
+
+		entry := entries[i]
+		date, err := e.parseTradeDate(entry.Date)
+		if err != nil {
+			e.logger.Warnf("skipping journal entry %d with unparseable date %q", entry.ID, entry.Date)
+			continue
+		}
+		key := date.Format(fileDateFmt)
+
+		// An existing day file keeps its form; new ones follow --compress
+		day, compressed, err := e.loadDayExport(key)
+		if errors.Is(err, os.ErrNotExist) {
+			compressed = opts.Compress
+			day = &models.DayExport{
+				Date:       key,
+				Trades:     []models.Trade{},
+				ExportedAt: time.Now(),
+				Partial:    entry.TradeCount > 0, // the day's trades aren't exported yet
+			}
+		} else if err != nil {
+			return fmt.Errorf("reading %s: %w", key, err)
+		}
+
+		if day.Journal != nil && !opts.Force {
+			skipped++
+			continue
+		}
+
+		day.Journal = &entry
+		if err := e.saveDayExport(day, compressed); err != nil {
+			return fmt.Errorf("saving %s: %w", key, err)
+		}
+		written++
+		e.infof("  %s: journal entry (%d trades)", key, entry.TradeCount)
+	}
+
+	if skipped > 0 {
+		e.logger.Infof("Skipped %d days that already have a journal entry (use --force to refresh)", skipped)
+	}
+	e.logger.Infof("Journal export complete: %d entries", written)
+	return nil
+}
+
+// resolveJournalRange picks the journal date range. Unlike trade exports it
+// is not incremental: without --from it starts at the first trade date.
+func (e *Exporter) resolveJournalRange(ctx context.Context, opts Options) (time.Time, time.Time, error) {
+	if opts.FromDate == "" {
+		if state, _ := e.loadState(); state != nil && state.FirstTradeDate != "" {
+			opts.FromDate = state.FirstTradeDate
+		}
+	}
+	return e.resolveRange(ctx, opts, nil)
+}
+
+// fetchAllJournal retrieves all journal entries in a date range with pagination.
+func (e *Exporter) fetchAllJournal(ctx context.Context, start, end time.Time) ([]models.JournalEntry, error) {
+	startStr := start.Format(tvDateFmt)
+	endStr := end.Format(tvDateFmt)
+
+	var all []models.JournalEntry
+	page := 1
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("fetching journal page %d: %w", page, err)
+		}
+		entries, err := e.client.ListJournal(ctx, startStr, endStr, page)
+		if err != nil {
+			return nil, fmt.Errorf("fetching journal page %d: %w", page, err)
+		}
+		if len(entries) == 0 {
+			break
+		}
+		all = append(all, entries...)
+
+		if len(entries) < 100 {
+			break
+		}
+		page++
+	}
+
+	return all, nil
+}
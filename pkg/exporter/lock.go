@@ -0,0 +1,137 @@
+package exporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+const lockFile = ".lock"
+
+// staleGuard is how old a takeover guard file (see takeOver) must be
+// before it is assumed to be left by a process that died holding it.
+const staleGuard = time.Minute
+
+// ErrLocked is returned by Run, RunJournal, ImportCSV, and Unbundle when
+// another of them holds the data directory's lock file.
+var ErrLocked = errors.New("another export is in progress")
+
+// lockInfo is what the lock file holds: who took the lock, and when.
+type lockInfo struct {
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// lock takes the data directory's lock file, so two exports (or imports)
+// can't read and write the same files at the same time. The file is created exclusively,
+// which works the same on every platform. A lock left by a process that
+// is no longer running (one killed outright, say) is taken over. The
+// returned func releases the lock.
+func (e *Exporter) lock() (func(), error) {
+	if err := os.MkdirAll(e.dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating data directory: %w", err)
+	}
+	path := filepath.Join(e.dataDir, lockFile)
+
+	data, err := json.Marshal(lockInfo{PID: os.Getpid(), StartedAt: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, werr := f.Write(data)
+			if cerr := f.Close(); werr == nil {
+				werr = cerr
+			}
+			if werr != nil {
+				os.Remove(path)
+				return nil, fmt.Errorf("writing lock file: %w", werr)
+			}
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("creating lock file: %w", err)
+		}
+
+		var held lockInfo
+		raw, rerr := os.ReadFile(path)
+		if rerr == nil {
+			rerr = json.Unmarshal(raw, &held)
+		}
+		if attempt == 0 && rerr == nil && held.PID > 0 && !processRunning(held.PID) {
+			if err := e.takeOver(path, held); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if rerr != nil || held.PID <= 0 {
+			return nil, fmt.Errorf("%w: %s exists; if no export is running, delete it", ErrLocked, path)
+		}
+		return nil, fmt.Errorf("%w (process %d, started %s); if it isn't running, delete %s",
+			ErrLocked, held.PID, held.StartedAt.Format(time.DateTime), path)
+	}
+}
+
+// takeOver removes a lock file left by a process that is no longer
+// running. Two exports could both find it stale, and the second would
+// then remove the lock the first had just taken, so a guard file created
+// exclusively lets only one of them at a time go ahead, and the lock is
+// read again under it: one that changed hands in the meantime is left
+// alone. The lock itself is then taken as usual, by the caller.
+func (e *Exporter) takeOver(path string, stale lockInfo) error {
+	guard := path + ".takeover"
+	g, err := os.OpenFile(guard, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, os.ErrExist) {
+		// A guard left by a process that died in the middle of a takeover
+		if info, serr := os.Stat(guard); serr == nil && time.Since(info.ModTime()) > staleGuard {
+			os.Remove(guard)
+			g, err = os.OpenFile(guard, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		}
+	}
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%w: another process is taking over the stale lock file %s", ErrLocked, path)
+	}
+	if err != nil {
+		return fmt.Errorf("creating lock file: %w", err)
+	}
+	g.Close()
+	defer os.Remove(guard)
+
+	var held lockInfo
+	raw, err := os.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(raw, &held)
+	}
+	if err != nil || held.PID != stale.PID || !held.StartedAt.Equal(stale.StartedAt) {
+		// Released or taken over since; the caller tries again
+		return nil
+	}
+
+	e.logger.Warnf("removing a stale lock file left by process %d, started %s", held.PID, held.StartedAt.Format(time.DateTime))
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing stale lock file: %w", err)
+	}
+	return nil
+}
+
+// processRunning reports whether a process with the given ID exists.
+func processRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// On Windows, FindProcess fails for a process that doesn't exist, and
+	// signals other than Kill aren't supported
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,152 @@
+package exporter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+)
+
+// deadPID is a process ID that no running process has.
+const deadPID = 1<<22 - 3
+
+// writeStaleLock leaves a lock file in dir as a process killed outright
+// would.
+func writeStaleLock(t *testing.T, dir string) {
+	t.Helper()
+	if processRunning(deadPID) {
+		t.Skipf("process %d exists", deadPID)
+	}
+	data, err := json.Marshal(lockInfo{PID: deadPID, StartedAt: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, lockFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLockHeld(t *testing.T) {
+	e := &Exporter{dataDir: t.TempDir(), logger: logging.Discard}
+	unlock, err := e.lock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.lock(); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second lock = %v, want ErrLocked", err)
+	}
+	unlock()
+	unlock, err = e.lock()
+	if err != nil {
+		t.Fatalf("lock after release: %v", err)
+	}
+	unlock()
+}
+
+func TestLockTakesOverStaleLock(t *testing.T) {
+	dir := t.TempDir()
+	writeStaleLock(t, dir)
+
+	e := &Exporter{dataDir: dir, logger: logging.Discard}
+	unlock, err := e.lock()
+	if err != nil {
+		t.Fatalf("lock over a stale lock: %v", err)
+	}
+	defer unlock()
+	if _, err := os.Stat(filepath.Join(dir, lockFile+".takeover")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("takeover guard left behind (%v)", err)
+	}
+}
+
+func TestLockStaleTakeoverRace(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		dir := t.TempDir()
+		writeStaleLock(t, dir)
+
+		const racers = 8
+		var mu sync.Mutex
+		var held []func()
+		var wg sync.WaitGroup
+		for i := 0; i < racers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				e := &Exporter{dataDir: dir, logger: logging.Discard}
+				unlock, err := e.lock()
+				if err != nil {
+					if !errors.Is(err, ErrLocked) {
+						t.Errorf("lock: %v", err)
+					}
+					return
+				}
+				mu.Lock()
+				held = append(held, unlock)
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		if len(held) != 1 {
+			t.Fatalf("round %d: %d exports hold the lock, want 1", round, len(held))
+		}
+		held[0]()
+	}
+}
+
+func TestLockOldTakeoverGuard(t *testing.T) {
+	dir := t.TempDir()
+	writeStaleLock(t, dir)
+	guard := filepath.Join(dir, lockFile+".takeover")
+	if err := os.WriteFile(guard, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := &Exporter{dataDir: dir, logger: logging.Discard}
+	if _, err := e.lock(); !errors.Is(err, ErrLocked) {
+		t.Fatalf("lock during another takeover = %v, want ErrLocked", err)
+	}
+
+	// A guard this old was left by a process that died holding it
+	old := time.Now().Add(-2 * staleGuard)
+	if err := os.Chtimes(guard, old, old); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := e.lock()
+	if err != nil {
+		t.Fatalf("lock past an abandoned guard: %v", err)
+	}
+	unlock()
+}
+
+func TestTakeOverLeavesLockThatChangedHands(t *testing.T) {
+	dir := t.TempDir()
+	writeStaleLock(t, dir)
+	path := filepath.Join(dir, lockFile)
+	var stale lockInfo
+	raw, _ := os.ReadFile(path)
+	if err := json.Unmarshal(raw, &stale); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another export takes the stale lock over between this one reading it
+	// and removing it
+	other := &Exporter{dataDir: dir, logger: logging.Discard}
+	unlock, err := other.lock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	e := &Exporter{dataDir: dir, logger: logging.Discard}
+	if err := e.takeOver(path, stale); err != nil {
+		t.Fatalf("takeOver: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("takeOver removed the other export's lock: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package exporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/atomicfile"
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+const manifestFile = "manifest.json"
+
+// ManifestProblem describes a day file that doesn't match the manifest.
+type ManifestProblem struct {
+	Date    string
+	Problem string
+}
+
+// ManifestReport is the result of checking day files against the manifest.
+type ManifestReport struct {
+	Checked   int
+	Problems  []ManifestProblem
+	Untracked []string // day files with no manifest entry (e.g. exported before manifests existed)
+}
+
+// recordManifest stores the checksum of a freshly written day file and
+// rewrites the manifest atomically.
+func (e *Exporter) recordManifest(day *models.DayExport, data []byte) error {
+	if e.manifest == nil {
+		m, err := loadManifest(e.dataDir)
+		if err != nil {
+			return err
+		}
+		e.manifest = m
+	}
+
+	sum := sha256.Sum256(data)
+	e.manifest.Days[day.Date] = models.ManifestEntry{
+		SHA256:    hex.EncodeToString(sum[:]),
+		Trades:    len(day.Trades),
+		WrittenAt: time.Now(),
+		Partial:   day.Partial,
+	}
+
+	out, err := json.MarshalIndent(e.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.Write(filepath.Join(e.dataDir, manifestFile), out, 0644); err != nil {
+		return fmt.Errorf("updating manifest: %w", err)
+	}
+	return nil
+}
+
+// CheckManifest re-hashes every day file in dataDir and reports files that
+// are missing, changed, or unreadable compared to the manifest.
+func CheckManifest(dataDir string) (*ManifestReport, error) {
+	m, err := loadManifest(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ManifestReport{}
+
+	dates := make([]string, 0, len(m.Days))
+	for date := range m.Days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	for _, date := range dates {
+		entry := m.Days[date]
+		report.Checked++
+
+		f, err := dayfile.Find(dataDir, date)
+		if err != nil {
+			report.Problems = append(report.Problems, ManifestProblem{Date: date, Problem: "missing day file"})
+			continue
+		}
+		data, err := dayfile.ReadRaw(f.Path)
+		if err != nil {
+			report.Problems = append(report.Problems, ManifestProblem{Date: date, Problem: "missing day file"})
+			continue
+		}
+
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) == entry.SHA256 {
+			continue
+		}
+
+		day, err := dayfile.Decode(f.Path, data)
+		if err != nil {
+			report.Problems = append(report.Problems, ManifestProblem{Date: date, Problem: "checksum mismatch, file does not parse (truncated write?)"})
+			continue
+		}
+		report.Problems = append(report.Problems, ManifestProblem{
+			Date:    date,
+			Problem: fmt.Sprintf("checksum mismatch (%d trades on disk, %d when written)", len(day.Trades), entry.Trades),
+		})
+	}
+
+	e := &Exporter{dataDir: dataDir}
+	onDisk, err := e.existingDates()
+	if err != nil {
+		return nil, err
+	}
+	for date := range onDisk {
+		if _, ok := m.Days[date]; !ok {
+			report.Untracked = append(report.Untracked, date)
+		}
+	}
+	sort.Strings(report.Untracked)
+
+	return report, nil
+}
+
+// loadManifest reads the manifest, returning an empty one if none exists yet.
+func loadManifest(dataDir string) (*models.Manifest, error) {
+	m := &models.Manifest{Days: make(map[string]models.ManifestEntry)}
+
+	data, err := os.ReadFile(filepath.Join(dataDir, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("corrupt manifest: %w", err)
+	}
+	if m.Days == nil {
+		m.Days = make(map[string]models.ManifestEntry)
+	}
+	return m, nil
+}
@@ -0,0 +1,152 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// mergeResult counts how a fetch changed an existing day file.
+type mergeResult struct {
+	existed bool // there was a day file to merge into
+	added   int  // trades not in the file before
+	updated int  // trades already in the file whose record changed
+	kept    int  // trades in the file that a filtered fetch didn't return
+	removed int  // trades in the file that a full fetch no longer returned
+}
+
+// mergeExisting folds the trades already saved for day.Date into day, so a
+// re-export never duplicates a trade and records that changed (say, a
+// trade that was open and has since closed) are updated in place. Fetched
+// records win. Saved trades the fetch didn't return are kept when opts
+// filter by symbol or tag, since the fetch never asked for them; an
+// unfiltered fetch is the whole day, so they are dropped (deleted in
+// Tradervue, or moved to another day by a new timezone).
+//
+// Only the trade list is replaced. Saved executions survive for every
+// trade still in the file that this run didn't fetch executions for (say,
+// a --force re-export without --with-executions), and the saved journal
+// entry is kept, since trade exports never fetch one. A filtered fetch
+// merged into a complete file leaves it complete; see
+// models.DayExport.Partial.
+func (e *Exporter) mergeExisting(day *models.DayExport, opts Options) mergeResult {
+	var res mergeResult
+
+	old, _, err := e.loadDayExport(day.Date)
+	if errors.Is(err, os.ErrNotExist) {
+		res.added = len(day.Trades)
+		return res
+	}
+	if err != nil {
+		// Nothing to salvage from a file that doesn't parse; overwrite it
+		e.logger.Warnf("replacing unreadable day file for %s: %v", day.Date, err)
+		res.added = len(day.Trades)
+		return res
+	}
+	res.existed = true
+
+	fetched := make(map[int]int, len(day.Trades)) // trade ID -> index in day.Trades
+	for i, t := range day.Trades {
+		fetched[t.ID] = i
+	}
+
+	var merged []models.Trade
+	seen := make(map[int]bool, len(old.Trades))
+	for _, t := range old.Trades {
+		seen[t.ID] = true
+		i, ok := fetched[t.ID]
+		if !ok {
+			if opts.filtered() {
+				merged = append(merged, t)
+				res.kept++
+			} else {
+				res.removed++
+			}
+			continue
+		}
+		if !reflect.DeepEqual(t, day.Trades[i]) {
+			res.updated++
+		}
+		merged = append(merged, day.Trades[i])
+	}
+	for _, t := range day.Trades {
+		if !seen[t.ID] {
+			merged = append(merged, t)
+			res.added++
+		}
+	}
+
+	day.Trades = merged
+
+	for _, t := range day.Trades {
+		execs, ok := old.Executions[t.ID]
+		if !ok {
+			continue
+		}
+		if _, fetched := day.Executions[t.ID]; fetched {
+			continue
+		}
+		if day.Executions == nil {
+			day.Executions = make(map[int][]models.Execution)
+		}
+		day.Executions[t.ID] = execs
+	}
+	if day.Journal == nil {
+		day.Journal = old.Journal
+	}
+	day.Partial = day.Partial && old.Partial
+	return res
+}
+
+// String describes the merge for the export log, e.g. "2 new, 1 updated".
+func (r mergeResult) String() string {
+	s := fmt.Sprintf("%d new, %d updated", r.added, r.updated)
+	if r.kept > 0 {
+		s += fmt.Sprintf(", %d kept", r.kept)
+	}
+	if r.removed > 0 {
+		s += fmt.Sprintf(", %d removed", r.removed)
+	}
+	return s
+}
+
+// clearMovedDays empties the trade list of the day files from start to end
+// that a --force fetch of the whole range returned no trades for, so a
+// trade that now falls on another day (under a new timezone, say) isn't
+// saved on both. Journal entries are kept.
+func (e *Exporter) clearMovedDays(start, end time.Time, byDate map[string][]models.Trade, opts Options) error {
+	files, err := dayfile.List(e.dataDir)
+	if err != nil {
+		return err
+	}
+	from, to := start.Format(fileDateFmt), end.Format(fileDateFmt)
+	for _, f := range files {
+		date := f.Date
+		if date < from || date > to || len(byDate[date]) > 0 {
+			continue
+		}
+		day, compressed, err := e.loadDayExport(date)
+		if err != nil || len(day.Trades) == 0 {
+			continue
+		}
+
+		n := len(day.Trades)
+		day.Trades = []models.Trade{}
+		day.Executions = nil
+		day.Partial = false
+		if err := e.saveDayExport(day, compressed); err != nil {
+			return fmt.Errorf("saving %s: %w", date, err)
+		}
+		if err := e.updateSummaryCache(date, nil, false); err != nil {
+			return fmt.Errorf("saving summary for %s: %w", date, err)
+		}
+		e.noteOpenTrades(date, day.Trades)
+		e.infof("  %s: cleared %d trades that no longer fall on this day", date, n)
+	}
+	return nil
+}
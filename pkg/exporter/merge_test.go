@@ -0,0 +1,179 @@
+package exporter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+func TestForceReexportKeepsExecutionsAndJournal(t *testing.T) {
+	const date = "2025-01-02"
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, dayfile.Dir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	e := New(nil, dir)
+	e.SetLogger(logging.Discard)
+
+	exit := 101.0
+	saved := &models.DayExport{
+		Date: date,
+		Trades: []models.Trade{
+			{ID: 1, Symbol: "AAPL", Open: true, Side: "L", EntryPrice: 100},
+			{ID: 2, Symbol: "TSLA", Side: "S", EntryPrice: 200, GrossPL: 15},
+		},
+		Executions: map[int][]models.Execution{
+			1: {{ID: 11, Symbol: "AAPL", Quantity: 100, Price: 100}},
+			2: {{ID: 21, Symbol: "TSLA", Quantity: -50, Price: 200}, {ID: 22, Symbol: "TSLA", Quantity: 50, Price: 199.7}},
+		},
+		Journal:    &models.JournalEntry{ID: 7, Date: date, Notes: "patient day"},
+		ExportedAt: time.Now(),
+	}
+	if err := e.saveDayExport(saved, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// A --force re-export without --with-executions fetches only trades;
+	// trade 1 has since closed
+	fetched := []models.Trade{
+		{ID: 1, Symbol: "AAPL", Side: "L", EntryPrice: 100, ExitPrice: &exit, GrossPL: 100},
+		{ID: 2, Symbol: "TSLA", Side: "S", EntryPrice: 200, GrossPL: 15},
+	}
+	if err := e.exportDay(context.Background(), date, fetched, Options{Force: true}); err != nil {
+		t.Fatalf("exportDay: %v", err)
+	}
+
+	got, _, err := e.loadDayExport(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Trades) != 2 {
+		t.Fatalf("day has %d trades, want 2", len(got.Trades))
+	}
+	for _, tr := range got.Trades {
+		if tr.ID == 1 && (tr.Open || tr.GrossPL != 100) {
+			t.Errorf("trade 1 not updated from the fetch: %+v", tr)
+		}
+	}
+	for id, want := range saved.Executions {
+		if n := len(got.Executions[id]); n != len(want) {
+			t.Errorf("trade %d has %d executions after re-export, want %d", id, n, len(want))
+		}
+	}
+	if got.Journal == nil || got.Journal.Notes != "patient day" {
+		t.Errorf("journal = %+v after re-export, want the saved entry", got.Journal)
+	}
+}
+
+func TestMergeDropsUnfetchedTradesOnlyWhenUnfiltered(t *testing.T) {
+	const date = "2025-01-02"
+	saved := &models.DayExport{
+		Date: date,
+		Trades: []models.Trade{
+			{ID: 1, Symbol: "AAPL", GrossPL: 10},
+			{ID: 2, Symbol: "TSLA", GrossPL: -5}, // since deleted in Tradervue
+		},
+		Executions: map[int][]models.Execution{
+			1: {{ID: 11, Symbol: "AAPL"}},
+			2: {{ID: 21, Symbol: "TSLA"}},
+		},
+		Journal: &models.JournalEntry{ID: 7, Date: date, Notes: "kept either way"},
+	}
+	fetched := []models.Trade{{ID: 1, Symbol: "AAPL", GrossPL: 10}}
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantIDs   []int
+		wantExecs []int
+	}{
+		{name: "unfiltered replaces the trade set", opts: Options{Force: true}, wantIDs: []int{1}, wantExecs: []int{1}},
+		{name: "symbol filter keeps other trades", opts: Options{Force: true, Symbols: []string{"AAPL"}}, wantIDs: []int{1, 2}, wantExecs: []int{1, 2}},
+		{name: "tag filter keeps other trades", opts: Options{Force: true, Tags: []string{"news"}}, wantIDs: []int{1, 2}, wantExecs: []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(dir, dayfile.Dir), 0755); err != nil {
+				t.Fatal(err)
+			}
+			e := New(nil, dir)
+			e.SetLogger(logging.Discard)
+			if err := e.saveDayExport(saved, false); err != nil {
+				t.Fatal(err)
+			}
+
+			day := &models.DayExport{Date: date, Trades: fetched, Partial: tt.opts.filtered()}
+			e.mergeExisting(day, tt.opts)
+
+			var ids []int
+			for _, tr := range day.Trades {
+				ids = append(ids, tr.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("trades = %v, want %v", ids, tt.wantIDs)
+			}
+			var execs []int
+			for id := range day.Executions {
+				execs = append(execs, id)
+			}
+			slices.Sort(execs)
+			if !slices.Equal(execs, tt.wantExecs) {
+				t.Errorf("executions for trades %v, want %v", execs, tt.wantExecs)
+			}
+			if day.Journal == nil {
+				t.Error("journal entry dropped")
+			}
+			if day.Partial {
+				t.Error("merging into a complete file left it partial")
+			}
+		})
+	}
+}
+
+func TestClearMovedDays(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, dayfile.Dir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	e := New(nil, dir)
+	e.SetLogger(logging.Discard)
+
+	days := []*models.DayExport{
+		{Date: "2025-01-02", Trades: []models.Trade{{ID: 1}}},                                                  // refetched
+		{Date: "2025-01-03", Trades: []models.Trade{{ID: 2}}, Journal: &models.JournalEntry{Notes: "keep me"}}, // trade moved
+		{Date: "2025-01-06", Trades: []models.Trade{{ID: 3}}},                                                  // outside the range
+	}
+	for _, d := range days {
+		if err := e.saveDayExport(d, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+	byDate := map[string][]models.Trade{"2025-01-02": {{ID: 1}, {ID: 2}}}
+	if err := e.clearMovedDays(start, end, byDate, Options{Force: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	for date, want := range map[string]int{"2025-01-02": 1, "2025-01-03": 0, "2025-01-06": 1} {
+		day, _, err := e.loadDayExport(date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(day.Trades) != want {
+			t.Errorf("%s has %d trades, want %d", date, len(day.Trades), want)
+		}
+	}
+	if day, _, _ := e.loadDayExport("2025-01-03"); day.Journal == nil {
+		t.Error("clearing 2025-01-03 dropped its journal entry")
+	}
+}
@@ -0,0 +1,42 @@
+package exporter
+
+import (
+	"os"
+
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+// writeNotes collects the notes of the exported trades between
+// opts.FromDate and opts.ToDate (all days when unset) into
+// opts.NotesFile, reading back the day files so days exported by earlier
+// runs are included.
+func (e *Exporter) writeNotes(opts Options) error {
+	gen := summary.NewGenerator(e.dataDir)
+	gen.SetLogger(e.logger)
+
+	rows, err := gen.Trades(summary.Options{
+		FromDate: opts.FromDate,
+		ToDate:   opts.ToDate,
+		Symbols:  opts.Symbols,
+		Tags:     opts.Tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(opts.NotesFile)
+	if err != nil {
+		return err
+	}
+	n, err := gen.ExportNotesMarkdown(f, rows)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	e.infof("Wrote notes of %d trades to %s", n, opts.NotesFile)
+	return nil
+}
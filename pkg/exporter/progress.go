@@ -0,0 +1,110 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+)
+
+// progress reports pagination through a long fetch. When the exporter logs
+// as text to a terminal it rewrites a single status line, unless the run is
+// quiet. Otherwise it logs one line per page, but only for verbose runs, so
+// redirected output such as a cron mail stays short.
+type progress struct {
+	log     logging.Logger
+	w       io.Writer // the terminal, when tty
+	tty     bool
+	quiet   bool
+	label   string
+	start   time.Time
+	pages   int
+	trades  int
+	lastLen int
+}
+
+// newProgress starts a progress report through the exporter's logger.
+// quiet hides it whatever the run's level, e.g. for a one-page probe.
+func (e *Exporter) newProgress(label string, quiet bool) *progress {
+	p := &progress{
+		log:   e.logger,
+		label: label,
+		start: time.Now(),
+	}
+	if tl, ok := e.logger.(*logging.TextLogger); ok {
+		if f, ok := tl.W.(*os.File); ok && isTerminal(f) {
+			p.w, p.tty = f, true
+		}
+	}
+	p.quiet = quiet || e.level == logging.LevelQuiet || (!p.tty && e.level < logging.LevelVerbose)
+	return p
+}
+
+// page records a fetched page of n trades. done is the fraction of the work
+// completed (0 to 1), used for the ETA; pass a negative value when unknown.
+func (p *progress) page(n int, done float64) {
+	p.pages++
+	p.trades += n
+	if p.quiet {
+		return
+	}
+
+	line := fmt.Sprintf("  %s: page %d, %d trades", p.label, p.pages, p.trades)
+	if eta, ok := p.eta(done); ok {
+		line += fmt.Sprintf(", ~%s left", eta)
+	}
+
+	if !p.tty {
+		p.log.Infof("%s", line)
+		return
+	}
+
+	// Pad over the previous line in case this one is shorter
+	pad := ""
+	if p.lastLen > len(line) {
+		pad = strings.Repeat(" ", p.lastLen-len(line))
+	}
+	fmt.Fprintf(p.w, "\r%s%s", line, pad)
+	p.lastLen = len(line)
+}
+
+// finish ends the status line so later log output starts on a fresh line.
+func (p *progress) finish() {
+	if p.quiet || !p.tty || p.lastLen == 0 {
+		return
+	}
+	fmt.Fprintln(p.w)
+	p.lastLen = 0
+}
+
+// eta projects the remaining time from the average pace so far.
+func (p *progress) eta(done float64) (time.Duration, bool) {
+	if done <= 0 || done >= 1 {
+		return 0, false
+	}
+	elapsed := time.Since(p.start)
+	remaining := time.Duration(float64(elapsed) * (1 - done) / done)
+	return remaining.Round(time.Second), true
+}
+
+// rangeDone estimates how much of [start, end] a newest-first fetch has
+// covered once it has reached the oldest trade date seen.
+func rangeDone(start, end, oldest time.Time) float64 {
+	total := end.Sub(start)
+	if total <= 0 {
+		return -1
+	}
+	return float64(end.Sub(oldest)) / float64(total)
+}
+
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,95 @@
+package exporter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Problem is a non-fatal failure during an export: something was skipped
+// or left incomplete, but the export carried on.
+type Problem struct {
+	Date    string // yyyy-mm-dd the problem affects; empty if unknown
+	Message string
+}
+
+// Report collects what one export run wrote and the problems it hit. A
+// report with no problems means the export fully succeeded.
+type Report struct {
+	mu       sync.Mutex
+	Problems []Problem
+	Exported []string // dates whose day file was written, in order
+	Trades   int      // trades in the written day files
+
+	// FeeMismatches lists trades whose fees disagree with their executions,
+	// with Options.ReconcileFees. They are not counted as problems.
+	FeeMismatches []FeeMismatch
+
+	// ExecutionGaps lists trades with executions on Tradervue that came
+	// back without any. They are not counted as problems either.
+	ExecutionGaps []ExecutionGap
+
+	// FailedExecutions lists the IDs of trades saved without executions
+	// because fetching them failed. Each is also a problem.
+	FailedExecutions []int
+}
+
+// OK reports whether the run finished without any problems.
+func (r *Report) OK() bool {
+	return r == nil || len(r.Problems) == 0
+}
+
+// Dates returns the sorted, distinct dates affected by problems.
+func (r *Report) Dates() []string {
+	seen := make(map[string]bool)
+	var dates []string
+	for _, p := range r.Problems {
+		if p.Date != "" && !seen[p.Date] {
+			seen[p.Date] = true
+			dates = append(dates, p.Date)
+		}
+	}
+	sort.Strings(dates)
+	return dates
+}
+
+func (r *Report) add(p Problem) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Problems = append(r.Problems, p)
+}
+
+func (r *Report) addExport(date string, trades int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Exported = append(r.Exported, date)
+	r.Trades += trades
+}
+
+func (r *Report) addFeeMismatch(m FeeMismatch) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.FeeMismatches = append(r.FeeMismatches, m)
+}
+
+func (r *Report) addFailedExecutions(ids []int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.FailedExecutions = append(r.FailedExecutions, ids...)
+}
+
+func (r *Report) addExecutionGap(g ExecutionGap) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ExecutionGaps = append(r.ExecutionGaps, g)
+}
+
+// warn logs a non-fatal problem and records it in the current run's report.
+// Safe for concurrent use.
+func (e *Exporter) warn(date, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	e.logger.Warnf("%s", msg)
+	if e.report != nil {
+		e.report.add(Problem{Date: date, Message: msg})
+	}
+}
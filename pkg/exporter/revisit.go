@@ -0,0 +1,161 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// revisitOpenDays re-fetches the days before start whose day file still
+// holds open trades, so trades that have since closed get their final
+// result. Days from start on are left to the export itself.
+func (e *Exporter) revisitOpenDays(ctx context.Context, opts Options, state *models.ExportState, start time.Time) error {
+	var days []time.Time
+	for _, d := range state.OpenTradeDates {
+		t, err := time.Parse(fileDateFmt, d)
+		if err != nil || !t.Before(start) {
+			continue
+		}
+		days = append(days, t)
+	}
+	if len(days) == 0 {
+		return nil
+	}
+
+	// One query per run of consecutive days, as --verify does for gaps
+	var runs []dateRange
+	for _, d := range days {
+		if n := len(runs); n > 0 && runs[n-1].end.AddDate(0, 0, 1).Equal(d) {
+			runs[n-1].end = d
+		} else {
+			runs = append(runs, dateRange{start: d, end: d})
+		}
+	}
+
+	if opts.DryRun {
+		e.logger.Infof("Would revisit %d days with open trades", len(days))
+		return nil
+	}
+	e.infof("Revisiting %d days with open trades...", len(days))
+
+	for _, r := range runs {
+		trades, err := e.fetchAllTrades(ctx, r.start, r.end, opts.Quiet)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("export interrupted: %w", context.Cause(ctx))
+			}
+			// Left in the state, so the next run tries again
+			e.warn("", "revisiting open trades from %s: %v", r.start.Format(fileDateFmt), err)
+			continue
+		}
+		byDate := e.groupTradesByDate(trades)
+		if opts.ClosedOnly {
+			e.dropOpenTrades(byDate)
+		}
+
+		for d := r.start; !d.After(r.end); d = d.AddDate(0, 0, 1) {
+			key := d.Format(fileDateFmt)
+			dayTrades, ok := byDate[key]
+			if !ok {
+				// All still open under --closed-only, or gone from
+				// Tradervue. Either way the day file still holds open
+				// trades, so it stays listed and is checked again next run.
+				if !e.open[key] {
+					e.infof("  %s: Tradervue no longer returns this day's trades; keeping the day file (re-export with --force to clear it)", key)
+				}
+				continue
+			}
+			if err := e.exportDay(ctx, key, dayTrades, opts); err != nil {
+				if ctx.Err() != nil {
+					return fmt.Errorf("export interrupted: %w", context.Cause(ctx))
+				}
+				e.warn(key, "%v", err)
+			}
+		}
+	}
+
+	e.recordOpenDays(state)
+	e.recordSettings(state, opts)
+	if err := e.saveState(state); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	return nil
+}
+
+// noteOpenTrades remembers whether a day file just written holds open
+// trades, for recordOpenDays. A day dropOpenTrades already marked stays
+// open.
+func (e *Exporter) noteOpenTrades(date string, trades []models.Trade) {
+	if e.open == nil || e.open[date] {
+		return
+	}
+	e.open[date] = len(closedTrades(trades)) < len(trades)
+}
+
+// dropOpenTrades removes open trades from byDate for --closed-only, and
+// marks their days open so later runs fetch them again until they close.
+// Days left with no trades are removed. It returns how many were dropped.
+func (e *Exporter) dropOpenTrades(byDate map[string][]models.Trade) int {
+	dropped := 0
+	for date, trades := range byDate {
+		closed := closedTrades(trades)
+		if len(closed) == len(trades) {
+			continue
+		}
+		dropped += len(trades) - len(closed)
+		if e.open != nil {
+			e.open[date] = true
+		}
+		if len(closed) == 0 {
+			delete(byDate, date)
+		} else {
+			byDate[date] = closed
+		}
+	}
+	return dropped
+}
+
+// closedTrades returns the trades that aren't open.
+func closedTrades(trades []models.Trade) []models.Trade {
+	var closed []models.Trade
+	for _, t := range trades {
+		if !t.Open {
+			closed = append(closed, t)
+		}
+	}
+	return closed
+}
+
+func hasTrade(trades []models.Trade, id int) bool {
+	for _, t := range trades {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// recordOpenDays updates the state's list of days with open trades from the
+// day files written so far this run.
+func (e *Exporter) recordOpenDays(state *models.ExportState) {
+	dates := make(map[string]bool, len(state.OpenTradeDates)+len(e.open))
+	for _, d := range state.OpenTradeDates {
+		dates[d] = true
+	}
+	for d, open := range e.open {
+		if open {
+			dates[d] = true
+		} else {
+			delete(dates, d)
+		}
+	}
+
+	state.OpenTradeDates = state.OpenTradeDates[:0]
+	for d := range dates {
+		state.OpenTradeDates = append(state.OpenTradeDates, d)
+	}
+	sort.Strings(state.OpenTradeDates)
+}
@@ -0,0 +1,106 @@
+// Package logging is the small logger interface the exporter and API client
+// report progress through, so programs embedding them can capture or
+// reformat their output instead of having it written to the global log.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// Logger receives the exporter's and API client's messages. Warnings are
+// non-fatal problems; debug messages only appear with --debug.
+type Logger interface {
+	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+	Debugf(format string, args ...any)
+}
+
+// Level is how much progress detail a command logs. Warnings and errors
+// are logged at every level.
+type Level int
+
+const (
+	// LevelQuiet logs only final summaries, warnings, and errors.
+	LevelQuiet Level = iota - 1
+	// LevelNormal adds routine progress, such as a line per day written.
+	LevelNormal
+	// LevelVerbose adds per-page progress and per-trade fetches.
+	LevelVerbose
+)
+
+// TextLogger writes one plain line per message, with warnings prefixed by
+// "Warning: ", the CLI's usual output. Safe for concurrent use.
+type TextLogger struct {
+	W  io.Writer
+	mu sync.Mutex
+}
+
+// NewText returns a TextLogger writing to w.
+func NewText(w io.Writer) *TextLogger {
+	return &TextLogger{W: w}
+}
+
+func (l *TextLogger) Infof(format string, args ...any) {
+	l.printf("", format, args...)
+}
+
+func (l *TextLogger) Warnf(format string, args ...any) {
+	l.printf("Warning: ", format, args...)
+}
+
+func (l *TextLogger) Debugf(format string, args ...any) {
+	l.printf("", format, args...)
+}
+
+func (l *TextLogger) printf(prefix, format string, args ...any) {
+	msg := prefix + fmt.Sprintf(format, args...)
+	if len(msg) == 0 || msg[len(msg)-1] != '\n' {
+		msg += "\n"
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	io.WriteString(l.W, msg)
+}
+
+// slogLogger adapts a *slog.Logger, e.g. one with a JSON handler.
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// FromSlog returns a Logger that sends each message to l at the matching
+// level.
+func FromSlog(l *slog.Logger) Logger {
+	return slogLogger{l: l}
+}
+
+func (s slogLogger) Infof(format string, args ...any) {
+	s.log(slog.LevelInfo, format, args...)
+}
+
+func (s slogLogger) Warnf(format string, args ...any) {
+	s.log(slog.LevelWarn, format, args...)
+}
+
+func (s slogLogger) Debugf(format string, args ...any) {
+	s.log(slog.LevelDebug, format, args...)
+}
+
+func (s slogLogger) log(level slog.Level, format string, args ...any) {
+	ctx := context.Background()
+	if s.l.Enabled(ctx, level) {
+		s.l.Log(ctx, level, fmt.Sprintf(format, args...))
+	}
+}
+
+// Discard drops every message.
+var Discard Logger = discard{}
+
+type discard struct{}
+
+func (discard) Infof(string, ...any)  {}
+func (discard) Warnf(string, ...any)  {}
+func (discard) Debugf(string, ...any) {}
@@ -0,0 +1,100 @@
+package models
+
+import "strings"
+
+// UnknownSymbol stands in for a trade's symbol when Tradervue returns it
+// empty, so it still gets its own row instead of a blank one.
+const UnknownSymbol = "UNKNOWN"
+
+// NormalizeSymbol trims whitespace from a symbol and maps an empty one to
+// UnknownSymbol.
+func NormalizeSymbol(symbol string) string {
+	symbol = strings.TrimSpace(symbol)
+	if symbol == "" {
+		return UnknownSymbol
+	}
+	return symbol
+}
+
+// NormalizeSymbols applies NormalizeSymbol to each trade in place and
+// returns the IDs of trades that had no symbol at all.
+func NormalizeSymbols(trades []Trade) []int {
+	var unknown []int
+	for i := range trades {
+		if strings.TrimSpace(trades[i].Symbol) == "" {
+			unknown = append(unknown, trades[i].ID)
+		}
+		trades[i].Symbol = NormalizeSymbol(trades[i].Symbol)
+	}
+	return unknown
+}
+
+// FilterSymbols returns the trades whose symbol is in symbols (case-insensitive).
+// An empty symbol list returns trades unchanged.
+func FilterSymbols(trades []Trade, symbols []string) []Trade {
+	if len(symbols) == 0 {
+		return trades
+	}
+
+	want := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		want[strings.ToUpper(s)] = true
+	}
+
+	var out []Trade
+	for _, t := range trades {
+		if want[strings.ToUpper(t.Symbol)] {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// ExcludeSymbols returns the trades whose symbol is not in symbols
+// (case-insensitive). An empty symbol list returns trades unchanged.
+func ExcludeSymbols(trades []Trade, symbols []string) []Trade {
+	if len(symbols) == 0 {
+		return trades
+	}
+
+	drop := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		drop[strings.ToUpper(s)] = true
+	}
+
+	var out []Trade
+	for _, t := range trades {
+		if !drop[strings.ToUpper(t.Symbol)] {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// FilterTags returns the trades tagged with any of tags, or with all of
+// them when matchAll is set (case-insensitive). An empty tag list returns
+// trades unchanged.
+func FilterTags(trades []Trade, tags []string, matchAll bool) []Trade {
+	if len(tags) == 0 {
+		return trades
+	}
+
+	var out []Trade
+	for _, t := range trades {
+		have := make(map[string]bool, len(t.Tags))
+		for _, tag := range t.Tags {
+			have[strings.ToLower(tag)] = true
+		}
+
+		matched := 0
+		for _, tag := range tags {
+			if have[strings.ToLower(tag)] {
+				matched++
+			}
+		}
+		if matched == len(tags) || (!matchAll && matched > 0) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
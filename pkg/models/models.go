@@ -0,0 +1,207 @@
+// Package models holds the Tradervue data types stored in day files and the
+// summaries computed from them.
+package models
+
+import "time"
+
+// Trade represents a single Tradervue trade.
+type Trade struct {
+	ID             int      `json:"id"`
+	Symbol         string   `json:"symbol"`
+	Volume         int      `json:"volume"`
+	Open           bool     `json:"open"`
+	Side           string   `json:"side"` // "L" = Long, "S" = Short
+	EntryPrice     float64  `json:"entry_price"`
+	ExitPrice      *float64 `json:"exit_price,omitempty"`
+	GrossPL        float64  `json:"gross_pl"`
+	NativePL       *float64 `json:"native_pl,omitempty"`
+	NativeCurrency *string  `json:"native_currency,omitempty"`
+	Commission     float64  `json:"commission"`
+	Fees           float64  `json:"fees"`
+	StartDatetime  string   `json:"start_datetime"`
+	EndDatetime    *string  `json:"end_datetime,omitempty"`
+	Duration       string   `json:"duration"` // "I" = Intraday, "M" = Multi-day
+	Notes          string   `json:"notes"`
+	NotesExcerpt   string   `json:"notes_excerpt"`
+	Tags           []string `json:"tags"`
+	Shared         bool     `json:"shared"`
+	InitialRisk    *float64 `json:"initial_risk,omitempty"`
+	ExecCount      int      `json:"exec_count"`
+	CommentCount   int      `json:"comment_count"`
+
+	// MFE/MAE fields
+	PositionMFE         *float64 `json:"position_mfe,omitempty"`
+	PositionMFEDatetime *string  `json:"position_mfe_datetime,omitempty"`
+	PositionMAE         *float64 `json:"position_mae,omitempty"`
+	PositionMAEDatetime *string  `json:"position_mae_datetime,omitempty"`
+	PriceMFE            *float64 `json:"price_mfe,omitempty"`
+	PriceMFEDatetime    *string  `json:"price_mfe_datetime,omitempty"`
+	PriceMAE            *float64 `json:"price_mae,omitempty"`
+	PriceMAEDatetime    *string  `json:"price_mae_datetime,omitempty"`
+	BestExitPL          *float64 `json:"best_exit_pl,omitempty"`
+	BestExitPLDatetime  *string  `json:"best_exit_pl_datetime,omitempty"`
+}
+
+// Execution represents a single fill/execution within a trade.
+type Execution struct {
+	ID         int     `json:"id"`
+	Datetime   string  `json:"datetime"`
+	Symbol     string  `json:"symbol"`
+	Quantity   int     `json:"quantity"` // positive = buy, negative = sell
+	Price      float64 `json:"price"`
+	Commission float64 `json:"commission"`
+	TransFee   float64 `json:"trans_fee"`
+	ECNFee     float64 `json:"ecn_fee"`
+
+	// Synthetic marks a stand-in built from the trade's entry and exit
+	// because Tradervue returned no executions (--synthesize-executions).
+	Synthetic bool `json:"synthetic,omitempty"`
+}
+
+// SyntheticExecutionID is the ID of a trade's synthetic entry (fill 0) or
+// exit (fill 1). It is negative, so it never clashes with a Tradervue ID,
+// and the same on every run, so re-imports update rather than add rows.
+func SyntheticExecutionID(tradeID, fill int) int {
+	return -(2*tradeID + fill + 1)
+}
+
+// JournalEntry represents a Tradervue daily journal entry.
+type JournalEntry struct {
+	ID           int     `json:"id"`
+	Date         string  `json:"date"`
+	Notes        string  `json:"notes"`
+	CommentCount int     `json:"comment_count"`
+	TradeCount   int     `json:"trade_count"`
+	TotalVolume  int     `json:"total_volume"`
+	GrossPL      float64 `json:"gross_pl"`
+	CommFees     float64 `json:"commfees"`
+	TradeIDs     []int   `json:"trade_ids"`
+}
+
+// DayExport holds all exported data for a single trading day.
+type DayExport struct {
+	Date       string              `json:"date"`
+	Trades     []Trade             `json:"trades"`
+	Executions map[int][]Execution `json:"executions,omitempty"`
+	Journal    *JournalEntry       `json:"journal,omitempty"`
+	ExportedAt time.Time           `json:"exported_at"`
+
+	// Partial marks a file that may lack some of the day's trades: one
+	// written by a --symbol or --tag export, or holding only a journal
+	// entry. An unfiltered export of the day clears it.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// ExportState tracks incremental export progress.
+type ExportState struct {
+	LastExportDate string    `json:"last_export_date"`
+	FirstTradeDate string    `json:"first_trade_date"`
+	TotalTrades    int       `json:"total_trades"`
+	TotalDays      int       `json:"total_days"`
+	LastRunAt      time.Time `json:"last_run_at"`
+
+	// KnownEmptyDays lists dates that --verify found to have no trades
+	// (weekends, holidays, days off), so they aren't re-fetched each time.
+	KnownEmptyDays []string `json:"known_empty_days,omitempty"`
+
+	// OpenTradeDates lists days whose day file holds trades that were still
+	// open when exported. Each export re-fetches them until they close.
+	OpenTradeDates []string `json:"open_trade_dates,omitempty"`
+
+	// Timezone is the zone the day files grouped trades into days by. The
+	// first export sets it, and only a --force re-export of the whole
+	// history replaces it. Day files written under another zone have
+	// different day boundaries.
+	Timezone string `json:"timezone,omitempty"`
+
+	// WithExecutions records whether exports fetched executions. Any export
+	// with executions sets it; a --force re-export of the whole history
+	// sets it to its own setting.
+	WithExecutions bool `json:"with_executions,omitempty"`
+}
+
+// Manifest records a checksum for every day file so corrupted or
+// truncated writes can be detected.
+type Manifest struct {
+	Days map[string]ManifestEntry `json:"days"` // keyed by yyyy-mm-dd
+}
+
+// ManifestEntry describes one day file as it was written.
+type ManifestEntry struct {
+	SHA256    string    `json:"sha256"`
+	Trades    int       `json:"trades"`
+	WrittenAt time.Time `json:"written_at"`
+	Partial   bool      `json:"partial,omitempty"` // see DayExport.Partial
+}
+
+// DailySummary is a computed summary for display.
+type DailySummary struct {
+	Date        string          `json:"date"`
+	TradeCount  int             `json:"trade_count"`
+	Symbols     []SymbolSummary `json:"symbols"`
+	GrossPL     float64         `json:"gross_pl"`
+	NetPL       float64         `json:"net_pl"`
+	Commission  float64         `json:"commission"`
+	Fees        float64         `json:"fees"`
+	TotalVolume int             `json:"total_volume"`
+	Winners     int             `json:"winners"`
+	Losers      int             `json:"losers"`
+	Scratches   int             `json:"scratches"` // break-even trades, counted as neither winners nor losers
+	WinRate     float64         `json:"win_rate"`
+
+	// OpenCount is the number of trades still open. Their P&L, commission,
+	// and fees are left out of the realized figures above until they close.
+	OpenCount      int    `json:"open_count"`
+	UnrealizedNote string `json:"unrealized_note,omitempty"`
+
+	// AvgRMultiple is the mean GrossPL / InitialRisk over RiskedTrades,
+	// the trades with a non-zero initial risk. Nil when there are none.
+	AvgRMultiple *float64 `json:"avg_r_multiple,omitempty"`
+	RiskedTrades int      `json:"risked_trades"`
+
+	// AvgHoldSeconds is the mean time between start and end over
+	// HeldTrades, the closed trades with both datetimes recorded.
+	AvgHoldSeconds float64 `json:"avg_hold_seconds"`
+	HeldTrades     int     `json:"held_trades"`
+	IntradayCount  int     `json:"intraday_count"` // Duration "I"
+	MultidayCount  int     `json:"multiday_count"` // Duration "M"
+
+	// AvgCaptureRatio is the mean GrossPL / PositionMFE over CaptureTrades,
+	// the winners with a positive MFE recorded: how much of the best move
+	// was kept. AvgMAE is the mean adverse excursion, as a positive dollar
+	// amount, over MAETrades, the closed trades with an MAE recorded. Both
+	// are nil when no trade has the data.
+	AvgCaptureRatio *float64 `json:"avg_capture_ratio,omitempty"`
+	CaptureTrades   int      `json:"capture_trades"`
+	AvgMAE          *float64 `json:"avg_mae,omitempty"`
+	MAETrades       int      `json:"mae_trades"`
+
+	// TotalNotional approximates the capital put to work: EntryPrice *
+	// Volume summed over the trades, open ones included, that have an entry
+	// price. MaxPositionNotional is the largest of those single trades.
+	TotalNotional       float64 `json:"total_notional"`
+	MaxPositionNotional float64 `json:"max_position_notional"`
+
+	// Currencies breaks realized P&L down by the currency each trade was
+	// made in, summing NativePL where Tradervue reports one.
+	Currencies []CurrencyPL `json:"currencies,omitempty"`
+}
+
+// CurrencyPL is realized P&L for the trades made in one currency.
+type CurrencyPL struct {
+	Currency string  `json:"currency"`
+	GrossPL  float64 `json:"gross_pl"` // in Currency, not the account currency
+	Count    int     `json:"count"`
+}
+
+// SymbolSummary groups trades by symbol within a day.
+type SymbolSummary struct {
+	Symbol     string  `json:"symbol"`
+	Side       string  `json:"side"` // L, S, or "L/S" if both
+	GrossPL    float64 `json:"gross_pl"`
+	Commission float64 `json:"commission"`
+	Fees       float64 `json:"fees"`
+	NetPL      float64 `json:"net_pl"` // GrossPL less the symbol's own commission and fees
+	Volume     int     `json:"volume"`
+	Count      int     `json:"count"`
+}
@@ -0,0 +1,113 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// maxMissedListed is how many of the most recent missed days PrintActivity
+// names; JSON output has them all.
+const maxMissedListed = 10
+
+// Activity compares the days traded with the days the market was open.
+type Activity struct {
+	FirstDate string `json:"first_date"`
+	LastDate  string `json:"last_date"`
+	Calendar  string `json:"calendar"`
+
+	MarketDays    int     `json:"market_days"`     // weekdays that weren't holidays
+	ActiveDays    int     `json:"active_days"`     // market days with at least one trade
+	Participation float64 `json:"participation"`   // ActiveDays as a percentage of MarketDays
+	Holidays      int     `json:"holidays"`        // weekday holidays in range
+	OffMarketDays int     `json:"off_market_days"` // days traded while the market was closed
+
+	MissedDays []string `json:"missed_days"` // market days without a trade, oldest first
+}
+
+// ComputeActivity counts the market days of cal from from to to (yyyy-mm-dd,
+// inclusive) and how many of them summaries, sorted by date, have trades
+// on. Empty from or to default to the first or last summary's date.
+// Trades on weekends or holidays are counted apart, never as participation.
+func ComputeActivity(summaries []models.DailySummary, cal Calendar, from, to string) (Activity, error) {
+	if from == "" && len(summaries) > 0 {
+		from = summaries[0].Date
+	}
+	if to == "" && len(summaries) > 0 {
+		to = summaries[len(summaries)-1].Date
+	}
+	start, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return Activity{}, fmt.Errorf("invalid start date %q (use yyyy-mm-dd)", from)
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return Activity{}, fmt.Errorf("invalid end date %q (use yyyy-mm-dd)", to)
+	}
+	if end.Before(start) {
+		return Activity{}, fmt.Errorf("end date %s is before start date %s", to, from)
+	}
+
+	traded := make(map[string]bool, len(summaries))
+	for _, s := range summaries {
+		if s.TradeCount > 0 {
+			traded[s.Date] = true
+		}
+	}
+
+	a := Activity{FirstDate: from, LastDate: to, Calendar: cal.Name, MissedDays: []string{}}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		if !cal.IsMarketDay(d) {
+			if wd := d.Weekday(); wd != time.Saturday && wd != time.Sunday {
+				a.Holidays++
+			}
+			if traded[date] {
+				a.OffMarketDays++
+			}
+			continue
+		}
+
+		a.MarketDays++
+		if traded[date] {
+			a.ActiveDays++
+		} else {
+			a.MissedDays = append(a.MissedDays, date)
+		}
+	}
+
+	if a.MarketDays > 0 {
+		a.Participation = float64(a.ActiveDays) / float64(a.MarketDays) * 100
+	}
+	return a, nil
+}
+
+// PrintActivity writes participation as a labeled key/value block, naming
+// the most recent missed days.
+func PrintActivity(w io.Writer, a Activity) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Period:\t%s to %s (%s market calendar)\n", a.FirstDate, a.LastDate, a.Calendar)
+	fmt.Fprintf(tw, "Market days:\t%d (%d %s excluded)\n", a.MarketDays, a.Holidays, plural(a.Holidays, "holiday", "holidays"))
+	fmt.Fprintf(tw, "Days traded:\t%d\n", a.ActiveDays)
+	fmt.Fprintf(tw, "Participation:\t%.1f%%\n", a.Participation)
+	fmt.Fprintf(tw, "Missed days:\t%d\n", len(a.MissedDays))
+	if a.OffMarketDays > 0 {
+		fmt.Fprintf(tw, "Off-market days:\t%d (traded on a weekend or holiday; not counted)\n", a.OffMarketDays)
+	}
+
+	if n := len(a.MissedDays); n > 0 {
+		recent := a.MissedDays[max(0, n-maxMissedListed):]
+		label := "Missed:"
+		if n > len(recent) {
+			label = fmt.Sprintf("Last %d missed:", len(recent))
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", label, strings.Join(recent, ", "))
+	}
+
+	tw.Flush()
+}
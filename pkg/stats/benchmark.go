@@ -0,0 +1,217 @@
+package stats
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+// Close is a benchmark's closing price on one day.
+type Close struct {
+	Date  string // yyyy-mm-dd
+	Price float64
+}
+
+// LoadBenchmarkCSV reads date,close rows (yyyy-mm-dd dates, a header row
+// is optional) and returns them sorted by date.
+func LoadBenchmarkCSV(r io.Reader) ([]Close, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var closes []Close
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("line %d: want date,close", line)
+		}
+		date := strings.TrimSpace(rec[0])
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("line %d: invalid date %q (use yyyy-mm-dd)", line, date)
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(rec[1]), 64)
+		if err != nil || price <= 0 {
+			return nil, fmt.Errorf("line %d: invalid close %q", line, rec[1])
+		}
+		closes = append(closes, Close{Date: date, Price: price})
+	}
+
+	if len(closes) == 0 {
+		return nil, errors.New("no closes found")
+	}
+	sort.Slice(closes, func(i, j int) bool { return closes[i].Date < closes[j].Date })
+	return closes, nil
+}
+
+// Benchmark compares trading results with buying and holding a benchmark
+// over the same days.
+type Benchmark struct {
+	Symbol    string `json:"symbol"`
+	FirstDate string `json:"first_date"`
+	LastDate  string `json:"last_date"`
+
+	// The buy-and-hold position is entered at the last close before
+	// FirstDate (or on it, when there is none before) and valued at the
+	// last close on or before LastDate.
+	EntryDate       string  `json:"entry_date"`
+	ExitDate        string  `json:"exit_date"`
+	BenchmarkReturn float64 `json:"benchmark_return"` // percent
+
+	NetPL float64 `json:"net_pl"`
+
+	// Capital turns net P&L into a return comparable with the benchmark's.
+	// Without it, Return, BenchmarkPL, and Alpha are nil.
+	Capital     float64  `json:"capital,omitempty"`
+	Return      *float64 `json:"return,omitempty"`       // percent of Capital
+	BenchmarkPL *float64 `json:"benchmark_pl,omitempty"` // what Capital would have made holding
+	Alpha       *float64 `json:"alpha,omitempty"`        // Return minus BenchmarkReturn, percentage points
+
+	// Correlation is the Pearson correlation of each trading day's net P&L
+	// with the benchmark's return that day, over CorrelationDays days that
+	// have a close and a previous close. Nil with fewer than two such days
+	// or no variation.
+	Correlation     *float64 `json:"correlation,omitempty"`
+	CorrelationDays int      `json:"correlation_days"`
+}
+
+// CompareBenchmark measures summaries, sorted by date, against closes from
+// LoadBenchmarkCSV. capital may be zero when unknown.
+func CompareBenchmark(symbol string, summaries []models.DailySummary, closes []Close, capital float64) (Benchmark, error) {
+	b := Benchmark{Symbol: symbol, Capital: capital}
+	if len(summaries) == 0 {
+		return b, errors.New("no trading days to compare")
+	}
+	b.FirstDate = summaries[0].Date
+	b.LastDate = summaries[len(summaries)-1].Date
+
+	entry, ok := closeBefore(closes, b.FirstDate)
+	if !ok {
+		entry, ok = closeOnOrBefore(closes, b.FirstDate)
+	}
+	if !ok {
+		return b, fmt.Errorf("benchmark has no close on or before %s", b.FirstDate)
+	}
+	exit, _ := closeOnOrBefore(closes, b.LastDate)
+	if exit.Date <= entry.Date {
+		return b, fmt.Errorf("benchmark has no closes between %s and %s", b.FirstDate, b.LastDate)
+	}
+	b.EntryDate, b.ExitDate = entry.Date, exit.Date
+	benchReturn := exit.Price/entry.Price - 1
+	b.BenchmarkReturn = benchReturn * 100
+
+	for _, s := range summaries {
+		b.NetPL += s.NetPL
+	}
+	if capital > 0 {
+		ret := b.NetPL / capital * 100
+		benchPL := capital * benchReturn
+		alpha := ret - b.BenchmarkReturn
+		b.Return, b.BenchmarkPL, b.Alpha = &ret, &benchPL, &alpha
+	}
+
+	byDate := make(map[string]int, len(closes))
+	for i, c := range closes {
+		byDate[c.Date] = i
+	}
+	var pls, returns []float64
+	for _, s := range summaries {
+		i, ok := byDate[s.Date]
+		if !ok || i == 0 {
+			continue
+		}
+		pls = append(pls, s.NetPL)
+		returns = append(returns, closes[i].Price/closes[i-1].Price-1)
+	}
+	b.CorrelationDays = len(pls)
+	b.Correlation = correlation(pls, returns)
+
+	return b, nil
+}
+
+// closeBefore returns the last close strictly before date.
+func closeBefore(closes []Close, date string) (Close, bool) {
+	i := sort.Search(len(closes), func(i int) bool { return closes[i].Date >= date })
+	if i == 0 {
+		return Close{}, false
+	}
+	return closes[i-1], true
+}
+
+// closeOnOrBefore returns the last close on or before date.
+func closeOnOrBefore(closes []Close, date string) (Close, bool) {
+	i := sort.Search(len(closes), func(i int) bool { return closes[i].Date > date })
+	if i == 0 {
+		return Close{}, false
+	}
+	return closes[i-1], true
+}
+
+// correlation is the Pearson correlation of xs and ys, or nil when it is
+// undefined.
+func correlation(xs, ys []float64) *float64 {
+	n := float64(len(xs))
+	if len(xs) < 2 {
+		return nil
+	}
+	var sx, sy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+	}
+	mx, my := sx/n, sy/n
+
+	var cov, vx, vy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return nil
+	}
+	r := cov / math.Sqrt(vx*vy)
+	return &r
+}
+
+// PrintBenchmark writes a benchmark comparison as a labeled key/value block.
+func PrintBenchmark(w io.Writer, b Benchmark) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Period:\t%s to %s\n", b.FirstDate, b.LastDate)
+	fmt.Fprintf(tw, "%s buy and hold:\t%+.2f%% (close %s to %s)\n", b.Symbol, b.BenchmarkReturn, b.EntryDate, b.ExitDate)
+	fmt.Fprintf(tw, "Net P&L:\t%s\n", summary.FormatPL(b.NetPL))
+	if b.Return != nil {
+		fmt.Fprintf(tw, "Return:\t%+.2f%% of $%.2f\n", *b.Return, b.Capital)
+		fmt.Fprintf(tw, "%s on same capital:\t%s\n", b.Symbol, summary.FormatPL(*b.BenchmarkPL))
+		fmt.Fprintf(tw, "Alpha:\t%+.2f points\n", *b.Alpha)
+	} else {
+		fmt.Fprintf(tw, "Alpha:\tn/a (give --capital to compare returns)\n")
+	}
+	if b.Correlation != nil {
+		fmt.Fprintf(tw, "Correlation:\t%.2f (daily net P&L vs %s, %d days)\n", *b.Correlation, b.Symbol, b.CorrelationDays)
+	} else {
+		fmt.Fprintf(tw, "Correlation:\tn/a (too few days with a close, or no variation)\n")
+	}
+
+	tw.Flush()
+}
@@ -0,0 +1,173 @@
+package stats
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Calendar tells market days from weekends and market holidays.
+type Calendar struct {
+	Name string
+
+	// holiday returns the name of the holiday on a date, or "".
+	holiday func(date time.Time) string
+}
+
+// USMarketCalendar is the NYSE and Nasdaq full-day holiday schedule,
+// computed for any year: New Year's Day, Martin Luther King Jr. Day,
+// Washington's Birthday, Good Friday, Memorial Day, Juneteenth (from 2022),
+// Independence Day, Labor Day, Thanksgiving, and Christmas. Holidays on a
+// Saturday are observed the Friday before and those on a Sunday the Monday
+// after, except that New Year's Day on a Saturday is not made up. Early
+// closes count as market days, and one-off closures (national days of
+// mourning, storms) are not included.
+func USMarketCalendar() Calendar {
+	return Calendar{Name: "US", holiday: usHoliday}
+}
+
+// LoadHolidays reads a calendar of date,name rows (yyyy-mm-dd dates; the
+// name, a header row, and # comments are optional), for markets other than
+// the US. Weekends are still never market days.
+func LoadHolidays(name string, r io.Reader) (Calendar, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+
+	days := make(map[string]string)
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Calendar{}, err
+		}
+		date := strings.TrimSpace(rec[0])
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return Calendar{}, fmt.Errorf("line %d: invalid date %q (use yyyy-mm-dd)", line, date)
+		}
+		label := "holiday"
+		if len(rec) > 1 && strings.TrimSpace(rec[1]) != "" {
+			label = strings.TrimSpace(rec[1])
+		}
+		days[date] = label
+	}
+
+	if len(days) == 0 {
+		return Calendar{}, errors.New("no holidays found")
+	}
+	return Calendar{Name: name, holiday: func(d time.Time) string {
+		return days[d.Format("2006-01-02")]
+	}}, nil
+}
+
+// Holiday returns the name of the market holiday on date, or "" when there
+// is none.
+func (c Calendar) Holiday(date time.Time) string {
+	if c.holiday == nil {
+		return ""
+	}
+	return c.holiday(date)
+}
+
+// IsMarketDay reports whether the market is open on date: a weekday that is
+// not a holiday.
+func (c Calendar) IsMarketDay(date time.Time) bool {
+	if wd := date.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return false
+	}
+	return c.Holiday(date) == ""
+}
+
+// usHoliday names the US market holiday observed on d, if any.
+func usHoliday(d time.Time) string {
+	y := d.Year()
+	on := func(t time.Time) bool {
+		return t.Year() == d.Year() && t.YearDay() == d.YearDay()
+	}
+
+	fixed := []struct {
+		name  string
+		month time.Month
+		day   int
+		since int
+	}{
+		{"New Year's Day", time.January, 1, 0},
+		{"Juneteenth", time.June, 19, 2022},
+		{"Independence Day", time.July, 4, 0},
+		{"Christmas Day", time.December, 25, 0},
+	}
+	for _, h := range fixed {
+		if y < h.since {
+			continue
+		}
+		date := time.Date(y, h.month, h.day, 0, 0, 0, 0, time.UTC)
+		switch date.Weekday() {
+		case time.Saturday:
+			if h.month == time.January {
+				continue // would fall in the old year; not made up
+			}
+			date = date.AddDate(0, 0, -1)
+		case time.Sunday:
+			date = date.AddDate(0, 0, 1)
+		}
+		if on(date) {
+			return h.name
+		}
+	}
+
+	floating := []struct {
+		name string
+		date time.Time
+	}{
+		{"Martin Luther King Jr. Day", nthWeekday(y, time.January, time.Monday, 3)},
+		{"Washington's Birthday", nthWeekday(y, time.February, time.Monday, 3)},
+		{"Good Friday", easter(y).AddDate(0, 0, -2)},
+		{"Memorial Day", nthWeekday(y, time.May, time.Monday, -1)},
+		{"Labor Day", nthWeekday(y, time.September, time.Monday, 1)},
+		{"Thanksgiving Day", nthWeekday(y, time.November, time.Thursday, 4)},
+	}
+	for _, h := range floating {
+		if on(h.date) {
+			return h.name
+		}
+	}
+	return ""
+}
+
+// nthWeekday returns the nth given weekday of a month, or the last one
+// when n is -1.
+func nthWeekday(year int, month time.Month, wd time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(wd) + 7) % 7))
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(wd) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// easter returns Western Easter Sunday of a year (the anonymous Gregorian
+// algorithm).
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
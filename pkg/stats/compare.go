@@ -0,0 +1,145 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+// DateRange is an inclusive span of days.
+type DateRange struct {
+	From string `json:"from"` // yyyy-mm-dd
+	To   string `json:"to"`   // yyyy-mm-dd
+}
+
+// ParseDateRange parses FROM:TO, e.g. 2025-01-01:2025-01-31.
+func ParseDateRange(s string) (DateRange, error) {
+	from, to, ok := strings.Cut(s, ":")
+	r := DateRange{From: strings.TrimSpace(from), To: strings.TrimSpace(to)}
+	if !ok {
+		return r, fmt.Errorf("invalid range %q (use FROM:TO, e.g. 2025-01-01:2025-01-31)", s)
+	}
+	for _, d := range []string{r.From, r.To} {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return r, fmt.Errorf("invalid date %q in range %q (use yyyy-mm-dd)", d, s)
+		}
+	}
+	if r.From > r.To {
+		return r, fmt.Errorf("range %q ends before it starts", s)
+	}
+	return r, nil
+}
+
+func (r DateRange) String() string {
+	return r.From + " to " + r.To
+}
+
+// SymbolDelta is one symbol's results in two ranges.
+type SymbolDelta struct {
+	Symbol  string  `json:"symbol"`
+	TradesA int     `json:"trades_a"`
+	TradesB int     `json:"trades_b"`
+	NetPLA  float64 `json:"net_pl_a"`
+	NetPLB  float64 `json:"net_pl_b"`
+	Change  float64 `json:"change"` // NetPLB - NetPLA
+}
+
+// Comparison sets the results of range B beside those of range A, the
+// baseline. Changes are B minus A.
+type Comparison struct {
+	RangeA  DateRange     `json:"range_a"`
+	RangeB  DateRange     `json:"range_b"`
+	A       Lifetime      `json:"a"`
+	B       Lifetime      `json:"b"`
+	Symbols []SymbolDelta `json:"symbols"` // largest change first
+}
+
+// Compare computes both ranges' metrics from their summaries, each sorted
+// by date, and the per-symbol change in net P&L.
+func Compare(rangeA, rangeB DateRange, a, b []models.DailySummary) Comparison {
+	c := Comparison{RangeA: rangeA, RangeB: rangeB, A: Compute(a), B: Compute(b)}
+
+	bySymbol := make(map[string]*SymbolDelta)
+	add := func(summaries []models.DailySummary, inB bool) {
+		for _, s := range summaries {
+			for _, sym := range s.Symbols {
+				d, ok := bySymbol[sym.Symbol]
+				if !ok {
+					d = &SymbolDelta{Symbol: sym.Symbol}
+					bySymbol[sym.Symbol] = d
+				}
+				if inB {
+					d.TradesB += sym.Count
+					d.NetPLB += sym.NetPL
+				} else {
+					d.TradesA += sym.Count
+					d.NetPLA += sym.NetPL
+				}
+			}
+		}
+	}
+	add(a, false)
+	add(b, true)
+
+	for _, d := range bySymbol {
+		d.Change = d.NetPLB - d.NetPLA
+		c.Symbols = append(c.Symbols, *d)
+	}
+	sort.Slice(c.Symbols, func(i, j int) bool {
+		di, dj := math.Abs(c.Symbols[i].Change), math.Abs(c.Symbols[j].Change)
+		if di != dj {
+			return di > dj
+		}
+		return c.Symbols[i].Symbol < c.Symbols[j].Symbol
+	})
+	return c
+}
+
+// verdict labels a change as an improvement or a regression, where higher
+// is better.
+func verdict(change float64) string {
+	switch {
+	case change > 0.005:
+		return "better"
+	case change < -0.005:
+		return "worse"
+	}
+	return ""
+}
+
+// PrintComparison writes the two ranges side by side with the change in
+// each metric, marking improvements and regressions, followed by the
+// per-symbol changes.
+func PrintComparison(w io.Writer, c Comparison) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	a, b := c.A, c.B
+
+	fmt.Fprintf(tw, "\tA: %s\tB: %s\tCHANGE\t\n", c.RangeA, c.RangeB)
+	fmt.Fprintf(tw, "Trading days\t%d\t%d\t%+d\t\n", a.TradingDays, b.TradingDays, b.TradingDays-a.TradingDays)
+	fmt.Fprintf(tw, "Trades\t%d\t%d\t%+d\t\n", a.TotalTrades, b.TotalTrades, b.TotalTrades-a.TotalTrades)
+	fmt.Fprintf(tw, "Net P&L\t%s\t%s\t%s\t%s\n", summary.FormatPL(a.NetPL), summary.FormatPL(b.NetPL), summary.FormatPL(b.NetPL-a.NetPL), verdict(b.NetPL-a.NetPL))
+	fmt.Fprintf(tw, "Win rate\t%.1f%%\t%.1f%%\t%+.1f pts\t%s\n", a.WinRate, b.WinRate, b.WinRate-a.WinRate, verdict(b.WinRate-a.WinRate))
+	fmt.Fprintf(tw, "Avg daily P&L\t%s\t%s\t%s\t%s\n", summary.FormatPL(a.AvgDailyPL), summary.FormatPL(b.AvgDailyPL), summary.FormatPL(b.AvgDailyPL-a.AvgDailyPL), verdict(b.AvgDailyPL-a.AvgDailyPL))
+	fmt.Fprintf(tw, "Expectancy\t%s\t%s\t%s\t%s\n", summary.FormatPL(a.Expectancy), summary.FormatPL(b.Expectancy), summary.FormatPL(b.Expectancy-a.Expectancy), verdict(b.Expectancy-a.Expectancy))
+	tw.Flush()
+
+	if len(c.Symbols) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "SYMBOL\tA TRADES\tA NET P&L\tB TRADES\tB NET P&L\tCHANGE\t\n")
+	fmt.Fprintf(tw, "──────\t────────\t─────────\t────────\t─────────\t──────\t\n")
+	for _, d := range c.Symbols {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%s\t%s\t%s\n",
+			d.Symbol, d.TradesA, summary.FormatPL(d.NetPLA), d.TradesB, summary.FormatPL(d.NetPLB), summary.FormatPL(d.Change), verdict(d.Change))
+	}
+	tw.Flush()
+}
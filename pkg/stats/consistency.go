@@ -0,0 +1,115 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+// Consistency holds day-level behavioral metrics: how often days end green,
+// how long runs of green and red days last, and how big they are.
+type Consistency struct {
+	TradingDays int     `json:"trading_days"`
+	GreenDays   int     `json:"green_days"` // net P&L above zero
+	RedDays     int     `json:"red_days"`   // net P&L below zero
+	FlatDays    int     `json:"flat_days"`
+	GreenPct    float64 `json:"green_pct"` // of all trading days
+
+	// CurrentStreak is the run of days ending on the last day: positive for
+	// green days, negative for red ones, zero if the last day was flat.
+	CurrentStreak     int `json:"current_streak"`
+	LongestWinStreak  int `json:"longest_win_streak"`
+	LongestLossStreak int `json:"longest_loss_streak"`
+
+	AvgGreenDay float64 `json:"avg_green_day"` // average net P&L of green days
+	AvgRedDay   float64 `json:"avg_red_day"`   // average net P&L of red days, negative
+
+	// ProfitFactor is the sum of green days over the absolute sum of red
+	// days. It is nil when there are no red days, where it would be infinite.
+	ProfitFactor *float64 `json:"profit_factor"`
+}
+
+// ComputeConsistency derives consistency metrics from daily summaries sorted
+// by date. A flat day ends both kinds of streak.
+func ComputeConsistency(summaries []models.DailySummary) Consistency {
+	var c Consistency
+	c.TradingDays = len(summaries)
+
+	var greenSum, redSum float64
+	for _, s := range summaries {
+		switch {
+		case s.NetPL > 0:
+			c.GreenDays++
+			greenSum += s.NetPL
+			if c.CurrentStreak < 0 {
+				c.CurrentStreak = 0
+			}
+			c.CurrentStreak++
+			c.LongestWinStreak = max(c.LongestWinStreak, c.CurrentStreak)
+		case s.NetPL < 0:
+			c.RedDays++
+			redSum += s.NetPL
+			if c.CurrentStreak > 0 {
+				c.CurrentStreak = 0
+			}
+			c.CurrentStreak--
+			c.LongestLossStreak = max(c.LongestLossStreak, -c.CurrentStreak)
+		default:
+			c.FlatDays++
+			c.CurrentStreak = 0
+		}
+	}
+
+	if c.TradingDays > 0 {
+		c.GreenPct = float64(c.GreenDays) / float64(c.TradingDays) * 100
+	}
+	if c.GreenDays > 0 {
+		c.AvgGreenDay = greenSum / float64(c.GreenDays)
+	}
+	if c.RedDays > 0 {
+		c.AvgRedDay = redSum / float64(c.RedDays)
+		pf := greenSum / -redSum
+		c.ProfitFactor = &pf
+	}
+	return c
+}
+
+// PrintConsistency writes consistency metrics as a labeled key/value block.
+func PrintConsistency(w io.Writer, c Consistency) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Trading days:\t%d\n", c.TradingDays)
+	fmt.Fprintf(tw, "Green days:\t%.1f%% (%d green / %d red / %d flat)\n", c.GreenPct, c.GreenDays, c.RedDays, c.FlatDays)
+	fmt.Fprintf(tw, "Current streak:\t%s\n", formatStreak(c.CurrentStreak))
+	fmt.Fprintf(tw, "Longest win streak:\t%d days\n", c.LongestWinStreak)
+	fmt.Fprintf(tw, "Longest loss streak:\t%d days\n", c.LongestLossStreak)
+	fmt.Fprintf(tw, "Avg green day:\t%s\n", summary.FormatPL(c.AvgGreenDay))
+	fmt.Fprintf(tw, "Avg red day:\t%s\n", summary.FormatPL(c.AvgRedDay))
+	if c.ProfitFactor != nil {
+		fmt.Fprintf(tw, "Profit factor:\t%.2f\n", *c.ProfitFactor)
+	} else {
+		fmt.Fprintf(tw, "Profit factor:\tn/a (no red days)\n")
+	}
+
+	tw.Flush()
+}
+
+func formatStreak(n int) string {
+	switch {
+	case n > 0:
+		return fmt.Sprintf("%d green %s", n, plural(n, "day", "days"))
+	case n < 0:
+		return fmt.Sprintf("%d red %s", -n, plural(-n, "day", "days"))
+	}
+	return "none (last day flat)"
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
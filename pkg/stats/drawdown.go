@@ -0,0 +1,196 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+// Drawdown is one fall of cumulative net P&L below a running peak, from
+// that peak until equity got back to it.
+type Drawdown struct {
+	PeakDate     string  `json:"peak_date"` // empty when the fall started before the first day
+	TroughDate   string  `json:"trough_date"`
+	RecoveryDate string  `json:"recovery_date,omitempty"` // empty while not recovered
+	PeakEquity   float64 `json:"peak_equity"`
+	Amount       float64 `json:"amount"` // trough less peak, negative
+
+	// Percent is Amount as a share of the account at the peak: capital
+	// plus PeakEquity when a capital is given, else PeakEquity alone. It is
+	// nil when that is not above zero.
+	Percent *float64 `json:"percent,omitempty"`
+
+	// Days is the calendar days from the peak (or the first day) to the
+	// recovery, or to the last day for a drawdown still running.
+	Days int `json:"days"`
+}
+
+// Recovered reports whether equity got back to the peak.
+func (d Drawdown) Recovered() bool {
+	return d.RecoveryDate != ""
+}
+
+// DrawdownReport lists the worst drawdowns of an equity curve and the one
+// it is in now, if any.
+type DrawdownReport struct {
+	FirstDate string     `json:"first_date"`
+	LastDate  string     `json:"last_date"`
+	Capital   float64    `json:"capital,omitempty"`
+	Drawdowns []Drawdown `json:"drawdowns"`         // deepest first
+	Current   *Drawdown  `json:"current,omitempty"` // nil at a new high
+}
+
+// ComputeDrawdowns scans the equity curve of summaries, which must be
+// sorted by date, for drawdowns, keeping the top deepest (all of them when
+// top is 0). Equity starts from zero, so a losing first day already starts
+// one. capital, if above zero, is the account size Percent is taken of.
+func ComputeDrawdowns(summaries []models.DailySummary, capital float64, top int) DrawdownReport {
+	r := DrawdownReport{Capital: capital, Drawdowns: []Drawdown{}}
+	if len(summaries) == 0 {
+		return r
+	}
+	r.FirstDate = summaries[0].Date
+	r.LastDate = summaries[len(summaries)-1].Date
+
+	var all []Drawdown
+	var cur *Drawdown
+	equity, peak := 0.0, 0.0
+	peakDate := ""
+
+	for _, s := range summaries {
+		equity += s.NetPL
+		if equity >= peak {
+			if cur != nil {
+				cur.RecoveryDate = s.Date
+				cur.Days = daysBetween(startOf(*cur, r.FirstDate), s.Date)
+				all = append(all, *cur)
+				cur = nil
+			}
+			peak, peakDate = equity, s.Date
+			continue
+		}
+
+		if cur == nil {
+			cur = &Drawdown{PeakDate: peakDate, PeakEquity: peak}
+		}
+		if dd := equity - peak; dd < cur.Amount {
+			cur.Amount = dd
+			cur.TroughDate = s.Date
+		}
+	}
+	if cur != nil {
+		cur.Days = daysBetween(startOf(*cur, r.FirstDate), r.LastDate)
+		all = append(all, *cur)
+	}
+
+	for i := range all {
+		all[i].Percent = drawdownPercent(all[i], capital)
+	}
+	if n := len(all); n > 0 && !all[n-1].Recovered() {
+		c := all[n-1]
+		r.Current = &c
+	}
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Amount < all[j].Amount })
+	if top > 0 && len(all) > top {
+		all = all[:top]
+	}
+	r.Drawdowns = append(r.Drawdowns, all...)
+	return r
+}
+
+// startOf is the date a drawdown is counted from: its peak, or the first
+// day when it began before any gain.
+func startOf(d Drawdown, firstDate string) string {
+	if d.PeakDate == "" {
+		return firstDate
+	}
+	return d.PeakDate
+}
+
+// drawdownPercent is d's depth as a percentage of the account at its peak.
+func drawdownPercent(d Drawdown, capital float64) *float64 {
+	base := d.PeakEquity
+	if capital > 0 {
+		base += capital
+	}
+	if base <= 0 {
+		return nil
+	}
+	pct := d.Amount / base * 100
+	return &pct
+}
+
+// daysBetween counts the calendar days from one yyyy-mm-dd date to another.
+func daysBetween(from, to string) int {
+	a, err1 := time.Parse("2006-01-02", from)
+	b, err2 := time.Parse("2006-01-02", to)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// PrintDrawdowns writes the current drawdown and a table of the deepest.
+func PrintDrawdowns(w io.Writer, r DrawdownReport) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Period:\t%s to %s\n", r.FirstDate, r.LastDate)
+	if r.Capital > 0 {
+		fmt.Fprintf(tw, "Capital:\t$%.2f\n", r.Capital)
+	}
+	if len(r.Drawdowns) > 0 {
+		d := r.Drawdowns[0]
+		fmt.Fprintf(tw, "Max drawdown:\t%s%s, %s to %s\n", summary.FormatPL(d.Amount), formatPercent(d.Percent), peakLabel(d), d.TroughDate)
+	} else {
+		fmt.Fprintf(tw, "Max drawdown:\tnone (equity never fell below its peak)\n")
+	}
+	if c := r.Current; c != nil {
+		fmt.Fprintf(tw, "Current drawdown:\t%s%s from %s (%d days)\n", summary.FormatPL(c.Amount), formatPercent(c.Percent), peakLabel(*c), c.Days)
+	} else {
+		fmt.Fprintf(tw, "Current drawdown:\tnone (at a new high)\n")
+	}
+	tw.Flush()
+
+	if len(r.Drawdowns) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tPEAK\tTROUGH\tRECOVERED\tDEPTH\t%\tDAYS")
+	fmt.Fprintln(tw, "─\t────\t──────\t─────────\t─────\t─\t────")
+	for i, d := range r.Drawdowns {
+		recovered := d.RecoveryDate
+		if !d.Recovered() {
+			recovered = "not yet"
+		}
+		pct := "n/a"
+		if d.Percent != nil {
+			pct = fmt.Sprintf("%.1f%%", *d.Percent)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%d\n", i+1, peakLabel(d), d.TroughDate, recovered, summary.FormatPL(d.Amount), pct, d.Days)
+	}
+	tw.Flush()
+}
+
+// peakLabel is the date a drawdown fell from, or "start" when it began
+// before any gain.
+func peakLabel(d Drawdown) string {
+	if d.PeakDate == "" {
+		return "start"
+	}
+	return d.PeakDate
+}
+
+// formatPercent renders an optional percentage as " (-12.3%)", or nothing.
+func formatPercent(p *float64) string {
+	if p == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (%.1f%%)", *p)
+}
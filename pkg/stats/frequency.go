@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// flatTrend is the weekly change in trades per day below which the trend
+// is reported as flat.
+const flatTrend = 0.05
+
+// DayCount identifies a single day's number of trades.
+type DayCount struct {
+	Date   string `json:"date"`
+	Trades int    `json:"trades"`
+}
+
+// Frequency measures how often trades are taken and whether that is
+// changing over time, to spot overtrading.
+type Frequency struct {
+	FirstDate   string `json:"first_date"`
+	LastDate    string `json:"last_date"`
+	ActiveDays  int    `json:"active_days"` // days with at least one trade
+	TotalTrades int    `json:"total_trades"`
+
+	AvgTradesPerDay float64  `json:"avg_trades_per_day"` // per active day
+	BusiestDay      DayCount `json:"busiest_day"`
+	QuietestDay     DayCount `json:"quietest_day"`
+
+	// TrendPerWeek is the slope of a least-squares line through each active
+	// day's trade count over calendar time, as the change in trades per day
+	// each week. It is nil with fewer than two active days.
+	TrendPerWeek *float64 `json:"trend_per_week,omitempty"`
+}
+
+// ComputeFrequency derives trade frequency from daily summaries sorted by
+// date. Days without trades are left out, so the averages and the trend
+// are of days actually traded.
+func ComputeFrequency(summaries []models.DailySummary) Frequency {
+	var f Frequency
+	var xs, ys []float64
+	var first time.Time
+
+	for _, s := range summaries {
+		if s.TradeCount == 0 {
+			continue
+		}
+		d, err := time.Parse("2006-01-02", s.Date)
+		if err != nil {
+			continue
+		}
+		day := DayCount{Date: s.Date, Trades: s.TradeCount}
+		if f.ActiveDays == 0 {
+			first = d
+			f.FirstDate = s.Date
+			f.BusiestDay, f.QuietestDay = day, day
+		}
+		f.LastDate = s.Date
+		f.ActiveDays++
+		f.TotalTrades += s.TradeCount
+		if day.Trades > f.BusiestDay.Trades {
+			f.BusiestDay = day
+		}
+		if day.Trades < f.QuietestDay.Trades {
+			f.QuietestDay = day
+		}
+
+		xs = append(xs, d.Sub(first).Hours()/24)
+		ys = append(ys, float64(s.TradeCount))
+	}
+
+	if f.ActiveDays > 0 {
+		f.AvgTradesPerDay = float64(f.TotalTrades) / float64(f.ActiveDays)
+	}
+	if slope, ok := linearSlope(xs, ys); ok {
+		weekly := slope * 7
+		f.TrendPerWeek = &weekly
+	}
+	return f
+}
+
+// linearSlope fits y = a + bx by least squares and returns b. It fails when
+// the xs don't vary.
+func linearSlope(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0, false
+	}
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var sxx, sxy float64
+	for i := range xs {
+		dx := xs[i] - meanX
+		sxx += dx * dx
+		sxy += dx * (ys[i] - meanY)
+	}
+	if sxx == 0 {
+		return 0, false
+	}
+	return sxy / sxx, true
+}
+
+// PrintFrequency writes trade frequency as a labeled key/value block.
+func PrintFrequency(w io.Writer, f Frequency) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Period:\t%s to %s\n", f.FirstDate, f.LastDate)
+	fmt.Fprintf(tw, "Active days:\t%d (%d trades)\n", f.ActiveDays, f.TotalTrades)
+	fmt.Fprintf(tw, "Avg trades per day:\t%.1f\n", f.AvgTradesPerDay)
+	fmt.Fprintf(tw, "Busiest day:\t%d %s (%s)\n", f.BusiestDay.Trades, plural(f.BusiestDay.Trades, "trade", "trades"), f.BusiestDay.Date)
+	fmt.Fprintf(tw, "Quietest day:\t%d %s (%s)\n", f.QuietestDay.Trades, plural(f.QuietestDay.Trades, "trade", "trades"), f.QuietestDay.Date)
+	fmt.Fprintf(tw, "Trend:\t%s\n", formatTrend(f.TrendPerWeek))
+
+	tw.Flush()
+}
+
+// formatTrend renders the weekly change in trades per day in words.
+func formatTrend(perWeek *float64) string {
+	switch {
+	case perWeek == nil:
+		return "n/a (needs two active days)"
+	case math.Abs(*perWeek) < flatTrend:
+		return "flat"
+	case *perWeek > 0:
+		return fmt.Sprintf("increasing %.2f trades/week", *perWeek)
+	}
+	return fmt.Sprintf("decreasing %.2f trades/week", -*perWeek)
+}
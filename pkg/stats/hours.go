@@ -0,0 +1,97 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+// The regular US session, 9:30 to 16:00 New York time, as whole hours. The
+// hourly table always covers it when trades are read in that zone.
+const (
+	sessionZone      = "America/New_York"
+	sessionOpenHour  = 9
+	sessionCloseHour = 15 // the 15:00 bucket runs to the close
+)
+
+// HourStats is performance of the trades opened in one hour of the day.
+type HourStats struct {
+	Hour    int     `json:"hour"` // 0-23, in the zone the trades were read in
+	Trades  int     `json:"trades"`
+	GrossPL float64 `json:"gross_pl"`
+	NetPL   float64 `json:"net_pl"`
+	Winners int     `json:"winners"`
+	Losers  int     `json:"losers"`
+	WinRate float64 `json:"win_rate"` // scratches excluded
+}
+
+// ByHour buckets closed trades by the hour their start time falls in,
+// read in loc. Winners and losers are judged by summary.OutcomeOf on basis,
+// and trades within scratch of zero count toward P&L but not the win rate.
+// Hours run without gaps from the first to the last with trades, widened
+// to the regular session when loc is New York. Open trades and trades
+// whose start time doesn't parse are left out.
+func ByHour(rows []summary.DayTrade, loc *time.Location, scratch float64, basis summary.WinBasis) []HourStats {
+	var buckets [24]HourStats
+	first, last := 24, -1
+	for _, r := range rows {
+		t := r.Trade
+		if t.Open {
+			continue
+		}
+		start, err := time.Parse(time.RFC3339, t.StartDatetime)
+		if err != nil {
+			continue
+		}
+		h := start.In(loc).Hour()
+		first, last = min(first, h), max(last, h)
+
+		b := &buckets[h]
+		b.Trades++
+		b.GrossPL += t.GrossPL
+		b.NetPL += t.GrossPL - t.Commission - t.Fees
+		switch summary.OutcomeOf(t, scratch, basis) {
+		case summary.OutcomeWin:
+			b.Winners++
+		case summary.OutcomeLoss:
+			b.Losers++
+		}
+	}
+
+	if loc.String() == sessionZone {
+		first, last = min(first, sessionOpenHour), max(last, sessionCloseHour)
+	}
+
+	var out []HourStats
+	for h := first; h <= last; h++ {
+		b := buckets[h]
+		b.Hour = h
+		if b.Winners+b.Losers > 0 {
+			b.WinRate = float64(b.Winners) / float64(b.Winners+b.Losers) * 100
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// PrintHours writes the hourly breakdown as a table.
+func PrintHours(w io.Writer, hours []HourStats, loc *time.Location) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "HOUR (%s)\tTRADES\tGROSS P&L\tNET P&L\tWIN%%\n", loc)
+	fmt.Fprintln(tw, "──────────\t──────\t─────────\t───────\t────")
+	for _, h := range hours {
+		label := fmt.Sprintf("%02d:00-%02d:00", h.Hour, (h.Hour+1)%24)
+		if h.Trades == 0 {
+			fmt.Fprintf(tw, "%s\t0\t-\t-\t-\n", label)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%.0f%%\n",
+			label, h.Trades, summary.FormatPL(h.GrossPL), summary.FormatPL(h.NetPL), h.WinRate)
+	}
+
+	tw.Flush()
+}
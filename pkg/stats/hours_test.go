@@ -0,0 +1,47 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+func TestByHourOutcomes(t *testing.T) {
+	// Gross, all three clear the 0.50 scratch threshold; net of costs the
+	// second is a scratch
+	trades := []models.Trade{
+		{ID: 1, GrossPL: 4, Commission: 1, StartDatetime: "2025-01-02T10:05:00-05:00"},
+		{ID: 2, GrossPL: 0.60, Commission: 1, StartDatetime: "2025-01-02T10:20:00-05:00"},
+		{ID: 3, GrossPL: -1.50, Fees: 0.50, StartDatetime: "2025-01-02T10:40:00-05:00"},
+	}
+	var rows []summary.DayTrade
+	for _, tr := range trades {
+		rows = append(rows, summary.DayTrade{Date: "2025-01-02", Trade: tr})
+	}
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		basis           summary.WinBasis
+		winners, losers int
+	}{
+		{summary.WinBasisGross, 2, 1},
+		{summary.WinBasisNet, 1, 1},
+	}
+	for _, tt := range tests {
+		var got HourStats
+		for _, h := range ByHour(rows, loc, 0.5, tt.basis) {
+			if h.Hour == 10 {
+				got = h
+			}
+		}
+		if got.Trades != 3 || got.Winners != tt.winners || got.Losers != tt.losers {
+			t.Errorf("%s basis: 10:00 bucket has %d trades, %d winners, %d losers; want 3, %d, %d",
+				tt.basis, got.Trades, got.Winners, got.Losers, tt.winners, tt.losers)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Package stats derives lifetime metrics from daily summaries.
+package stats
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+// DayPL identifies a single day's net P&L.
+type DayPL struct {
+	Date  string  `json:"date"`
+	NetPL float64 `json:"net_pl"`
+}
+
+// Lifetime holds aggregate metrics across a set of daily summaries.
+type Lifetime struct {
+	FirstDate        string  `json:"first_date"`
+	LastDate         string  `json:"last_date"`
+	TradingDays      int     `json:"trading_days"`
+	TotalTrades      int     `json:"total_trades"`
+	OpenTrades       int     `json:"open_trades"`
+	GrossPL          float64 `json:"gross_pl"`
+	NetPL            float64 `json:"net_pl"`
+	Commission       float64 `json:"commission"`
+	Fees             float64 `json:"fees"`
+	Winners          int     `json:"winners"`
+	Losers           int     `json:"losers"`
+	Scratches        int     `json:"scratches"`
+	WinRate          float64 `json:"win_rate"`
+	BestDay          DayPL   `json:"best_day"`
+	WorstDay         DayPL   `json:"worst_day"`
+	LongestWinStreak int     `json:"longest_win_streak"` // consecutive days with positive net P&L
+	AvgDailyPL       float64 `json:"avg_daily_pl"`
+	Expectancy       float64 `json:"expectancy"` // average net P&L per trade
+
+	// MFE/MAE efficiency, weighted by the trades each day had data for.
+	// See models.DailySummary.
+	AvgCaptureRatio *float64 `json:"avg_capture_ratio,omitempty"`
+	CaptureTrades   int      `json:"capture_trades"`
+	AvgMAE          *float64 `json:"avg_mae,omitempty"`
+	MAETrades       int      `json:"mae_trades"`
+}
+
+// Compute derives lifetime metrics from daily summaries sorted by date.
+func Compute(summaries []models.DailySummary) Lifetime {
+	var l Lifetime
+	if len(summaries) == 0 {
+		return l
+	}
+
+	l.FirstDate = summaries[0].Date
+	l.LastDate = summaries[len(summaries)-1].Date
+	l.TradingDays = len(summaries)
+	l.BestDay = DayPL{Date: summaries[0].Date, NetPL: summaries[0].NetPL}
+	l.WorstDay = l.BestDay
+
+	streak := 0
+	var captureSum, maeSum float64
+	for _, s := range summaries {
+		l.TotalTrades += s.TradeCount
+		l.GrossPL += s.GrossPL
+		l.NetPL += s.NetPL
+		l.Commission += s.Commission
+		l.Fees += s.Fees
+		l.Winners += s.Winners
+		l.Losers += s.Losers
+		l.Scratches += s.Scratches
+		l.OpenTrades += s.OpenCount
+		if s.AvgCaptureRatio != nil {
+			captureSum += *s.AvgCaptureRatio * float64(s.CaptureTrades)
+			l.CaptureTrades += s.CaptureTrades
+		}
+		if s.AvgMAE != nil {
+			maeSum += *s.AvgMAE * float64(s.MAETrades)
+			l.MAETrades += s.MAETrades
+		}
+
+		if s.NetPL > l.BestDay.NetPL {
+			l.BestDay = DayPL{Date: s.Date, NetPL: s.NetPL}
+		}
+		if s.NetPL < l.WorstDay.NetPL {
+			l.WorstDay = DayPL{Date: s.Date, NetPL: s.NetPL}
+		}
+
+		if s.NetPL > 0 {
+			streak++
+			if streak > l.LongestWinStreak {
+				l.LongestWinStreak = streak
+			}
+		} else {
+			streak = 0
+		}
+	}
+
+	if l.Winners+l.Losers > 0 {
+		l.WinRate = float64(l.Winners) / float64(l.Winners+l.Losers) * 100
+	}
+	l.AvgDailyPL = l.NetPL / float64(l.TradingDays)
+	// Expectancy is per realized trade; open trades have no P&L yet
+	if closed := l.TotalTrades - l.OpenTrades; closed > 0 {
+		l.Expectancy = l.NetPL / float64(closed)
+	}
+	if l.CaptureTrades > 0 {
+		avg := captureSum / float64(l.CaptureTrades)
+		l.AvgCaptureRatio = &avg
+	}
+	if l.MAETrades > 0 {
+		avg := maeSum / float64(l.MAETrades)
+		l.AvgMAE = &avg
+	}
+
+	return l
+}
+
+// PrintLifetime writes lifetime metrics as a labeled key/value block.
+func PrintLifetime(w io.Writer, l Lifetime) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Period:\t%s to %s\n", l.FirstDate, l.LastDate)
+	fmt.Fprintf(tw, "Trading days:\t%d\n", l.TradingDays)
+	if l.OpenTrades > 0 {
+		fmt.Fprintf(tw, "Total trades:\t%d (%d still open, not in P&L)\n", l.TotalTrades, l.OpenTrades)
+	} else {
+		fmt.Fprintf(tw, "Total trades:\t%d\n", l.TotalTrades)
+	}
+	fmt.Fprintf(tw, "Gross P&L:\t%s\n", summary.FormatPL(l.GrossPL))
+	fmt.Fprintf(tw, "Net P&L:\t%s\n", summary.FormatPL(l.NetPL))
+	fmt.Fprintf(tw, "Commission + fees:\t$%.2f\n", l.Commission+l.Fees)
+	fmt.Fprintf(tw, "Win rate:\t%.1f%% (%d W / %d L / %d scratch)\n", l.WinRate, l.Winners, l.Losers, l.Scratches)
+	fmt.Fprintf(tw, "Best day:\t%s (%s)\n", summary.FormatPL(l.BestDay.NetPL), l.BestDay.Date)
+	fmt.Fprintf(tw, "Worst day:\t%s (%s)\n", summary.FormatPL(l.WorstDay.NetPL), l.WorstDay.Date)
+	fmt.Fprintf(tw, "Longest win streak:\t%d days\n", l.LongestWinStreak)
+	fmt.Fprintf(tw, "Avg daily P&L:\t%s\n", summary.FormatPL(l.AvgDailyPL))
+	fmt.Fprintf(tw, "Expectancy:\t%s per trade\n", summary.FormatPL(l.Expectancy))
+
+	tw.Flush()
+}
+
+// PrintEfficiency writes the MFE/MAE view: how much of each winner's best
+// move was captured, and how much heat trades took before closing.
+func PrintEfficiency(w io.Writer, l Lifetime) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Period:\t%s to %s\n", l.FirstDate, l.LastDate)
+	if l.AvgCaptureRatio != nil {
+		fmt.Fprintf(tw, "Avg capture ratio:\t%.0f%% of MFE (%d winners)\n", *l.AvgCaptureRatio*100, l.CaptureTrades)
+	} else {
+		fmt.Fprintf(tw, "Avg capture ratio:\tn/a (no winners with MFE data)\n")
+	}
+	if l.AvgMAE != nil {
+		fmt.Fprintf(tw, "Avg MAE:\t$%.2f per trade (%d trades)\n", *l.AvgMAE, l.MAETrades)
+	} else {
+		fmt.Fprintf(tw, "Avg MAE:\tn/a (no trades with MAE data)\n")
+	}
+
+	tw.Flush()
+}
+
+// WeekdayStats is performance on one day of the week.
+type WeekdayStats struct {
+	Weekday  string  `json:"weekday"`
+	Days     int     `json:"days"` // trading days
+	Trades   int     `json:"trades"`
+	NetPL    float64 `json:"net_pl"`
+	AvgNetPL float64 `json:"avg_net_pl"` // per trading day
+	Winners  int     `json:"winners"`
+	Losers   int     `json:"losers"`
+	WinRate  float64 `json:"win_rate"` // over trades, scratches excluded
+}
+
+// ByWeekday buckets daily summaries by day of the week, Monday first.
+// Monday to Friday are always present; weekend days only if they have data.
+func ByWeekday(summaries []models.DailySummary) []WeekdayStats {
+	var buckets [7]WeekdayStats // indexed by time.Weekday
+	for i := range buckets {
+		buckets[i].Weekday = time.Weekday(i).String()
+	}
+
+	for _, s := range summaries {
+		t, err := time.Parse("2006-01-02", s.Date)
+		if err != nil {
+			continue
+		}
+		b := &buckets[t.Weekday()]
+		b.Days++
+		b.Trades += s.TradeCount
+		b.NetPL += s.NetPL
+		b.Winners += s.Winners
+		b.Losers += s.Losers
+	}
+
+	order := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+	var out []WeekdayStats
+	for _, d := range order {
+		b := buckets[d]
+		if (d == time.Saturday || d == time.Sunday) && b.Days == 0 {
+			continue
+		}
+		if b.Days > 0 {
+			b.AvgNetPL = b.NetPL / float64(b.Days)
+		}
+		if b.Winners+b.Losers > 0 {
+			b.WinRate = float64(b.Winners) / float64(b.Winners+b.Losers) * 100
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// PrintWeekdays writes the weekday breakdown as a table.
+func PrintWeekdays(w io.Writer, days []WeekdayStats) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "WEEKDAY\tDAYS\tTRADES\tNET P&L\tAVG/DAY\tWIN%")
+	fmt.Fprintln(tw, "───────\t────\t──────\t───────\t───────\t────")
+	for _, d := range days {
+		if d.Days == 0 {
+			fmt.Fprintf(tw, "%s\t0\t0\t-\t-\t-\n", d.Weekday)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%.0f%%\n",
+			d.Weekday, d.Days, d.Trades, summary.FormatPL(d.NetPL), summary.FormatPL(d.AvgNetPL), d.WinRate)
+	}
+
+	tw.Flush()
+}
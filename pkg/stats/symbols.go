@@ -0,0 +1,136 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+// histogramWidth is the length of the longest bar in PrintSymbolHistogram.
+const histogramWidth = 30
+
+// SymbolActivity is how often one symbol was traded, and with what result.
+type SymbolActivity struct {
+	Symbol   string  `json:"symbol"`
+	Days     int     `json:"days"`   // trading days with the symbol
+	Trades   int     `json:"trades"` // trades in it, open ones included
+	NetPL    float64 `json:"net_pl"`
+	AvgTrade float64 `json:"avg_trade"` // NetPL / Trades
+}
+
+// ActivityBucket counts the symbols traded a number of times in a range.
+type ActivityBucket struct {
+	Label   string `json:"label"` // e.g. "2-5"
+	Min     int    `json:"min"`
+	Max     int    `json:"max"` // 0 means no upper bound
+	Symbols int    `json:"symbols"`
+	Trades  int    `json:"trades"`
+}
+
+// SymbolHistogram is the trading activity per symbol, most traded first,
+// with the symbols grouped by how many times each was traded.
+type SymbolHistogram struct {
+	Symbols []SymbolActivity `json:"symbols"`
+	Buckets []ActivityBucket `json:"buckets"`
+}
+
+// activityBuckets are the trade-count ranges symbols are grouped into.
+var activityBuckets = [][2]int{{1, 1}, {2, 5}, {6, 20}, {21, 50}, {51, 0}}
+
+// BySymbol totals trades, trading days, and net P&L per symbol from the
+// per-symbol rows of summaries.
+func BySymbol(summaries []models.DailySummary) SymbolHistogram {
+	bySymbol := make(map[string]*SymbolActivity)
+	for _, s := range summaries {
+		for _, sym := range s.Symbols {
+			a, ok := bySymbol[sym.Symbol]
+			if !ok {
+				a = &SymbolActivity{Symbol: sym.Symbol}
+				bySymbol[sym.Symbol] = a
+			}
+			a.Days++
+			a.Trades += sym.Count
+			a.NetPL += sym.NetPL
+		}
+	}
+
+	var h SymbolHistogram
+	h.Symbols = []SymbolActivity{}
+	for _, a := range bySymbol {
+		if a.Trades > 0 {
+			a.AvgTrade = a.NetPL / float64(a.Trades)
+		}
+		h.Symbols = append(h.Symbols, *a)
+	}
+	sort.Slice(h.Symbols, func(i, j int) bool {
+		a, b := h.Symbols[i], h.Symbols[j]
+		if a.Trades != b.Trades {
+			return a.Trades > b.Trades
+		}
+		return a.Symbol < b.Symbol
+	})
+
+	for _, r := range activityBuckets {
+		b := ActivityBucket{Min: r[0], Max: r[1]}
+		switch {
+		case b.Max == 0:
+			b.Label = fmt.Sprintf("%d+", b.Min)
+		case b.Min == b.Max:
+			b.Label = fmt.Sprintf("%d", b.Min)
+		default:
+			b.Label = fmt.Sprintf("%d-%d", b.Min, b.Max)
+		}
+		for _, a := range h.Symbols {
+			if a.Trades >= b.Min && (b.Max == 0 || a.Trades <= b.Max) {
+				b.Symbols++
+				b.Trades += a.Trades
+			}
+		}
+		h.Buckets = append(h.Buckets, b)
+	}
+	return h
+}
+
+// PrintSymbolHistogram writes each symbol's activity with a bar scaled to
+// its trade count, then how many symbols fall in each trade-count range.
+func PrintSymbolHistogram(w io.Writer, h SymbolHistogram) {
+	most := 0
+	if len(h.Symbols) > 0 {
+		most = h.Symbols[0].Trades
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SYMBOL\tDAYS\tTRADES\tNET P&L\tAVG/TRADE")
+	fmt.Fprintln(tw, "──────\t────\t──────\t───────\t─────────")
+	for _, a := range h.Symbols {
+		bar := ""
+		if most > 0 {
+			bar = strings.Repeat("█", max(1, a.Trades*histogramWidth/most))
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\n", a.Symbol, a.Days, a.Trades, summary.FormatPL(a.NetPL), summary.FormatPL(a.AvgTrade), bar)
+	}
+	tw.Flush()
+
+	total := 0
+	for _, a := range h.Symbols {
+		total += a.Trades
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIMES TRADED\tSYMBOLS\tTRADES\tSHARE")
+	fmt.Fprintln(tw, "────────────\t───────\t──────\t─────")
+	for _, b := range h.Buckets {
+		share := 0.0
+		if total > 0 {
+			share = float64(b.Trades) / float64(total) * 100
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.0f%%\n", b.Label, b.Symbols, b.Trades, share)
+	}
+	tw.Flush()
+}
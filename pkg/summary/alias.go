@@ -0,0 +1,71 @@
+package summary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// ParseAliases builds a symbol alias map from OLD=NEW pairs, so trades in a
+// renamed ticker (FB=META) roll up with the new one. Symbols are
+// case-insensitive and stored uppercase.
+//
+// Conflicts resolve as follows: when the same OLD appears twice, the later
+// pair wins; chains are followed to their end, so A=B and B=C both map A to
+// C; and a cycle such as A=B with B=A is an error.
+func ParseAliases(pairs []string) (map[string]string, error) {
+	direct := make(map[string]string)
+	for _, p := range pairs {
+		oldSym, newSym, ok := strings.Cut(p, "=")
+		oldSym = strings.ToUpper(strings.TrimSpace(oldSym))
+		newSym = strings.ToUpper(strings.TrimSpace(newSym))
+		if !ok || oldSym == "" || newSym == "" {
+			return nil, fmt.Errorf("invalid alias %q (use OLD=NEW, e.g. FB=META)", p)
+		}
+		if oldSym == newSym {
+			delete(direct, oldSym)
+			continue
+		}
+		direct[oldSym] = newSym
+	}
+
+	olds := make([]string, 0, len(direct))
+	for oldSym := range direct {
+		olds = append(olds, oldSym)
+	}
+	sort.Strings(olds)
+
+	aliases := make(map[string]string, len(direct))
+	for _, oldSym := range olds {
+		sym := oldSym
+		seen := map[string]bool{sym: true}
+		for {
+			next, ok := direct[sym]
+			if !ok {
+				break
+			}
+			if seen[next] {
+				return nil, fmt.Errorf("aliases for %s form a cycle", oldSym)
+			}
+			seen[next] = true
+			sym = next
+		}
+		aliases[oldSym] = sym
+	}
+	return aliases, nil
+}
+
+// ApplyAliases rewrites each trade's symbol through aliases in place. Only
+// the in-memory trades change; day files are left as exported.
+func ApplyAliases(trades []models.Trade, aliases map[string]string) {
+	if len(aliases) == 0 {
+		return
+	}
+	for i := range trades {
+		if sym, ok := aliases[strings.ToUpper(trades[i].Symbol)]; ok {
+			trades[i].Symbol = sym
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package summary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/atomicfile"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// AnnotationsFile is the file in the data directory holding local trade
+// annotations. It is never sent to Tradervue, and exports leave it alone.
+const AnnotationsFile = "annotations.json"
+
+// Unflagged labels the bucket of trades that carry no local flags.
+const Unflagged = "(unflagged)"
+
+// Annotation is the local review metadata of one trade.
+type Annotation struct {
+	Flags     []string  `json:"flags"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Annotations maps trade IDs to their local annotations.
+type Annotations map[int]Annotation
+
+// LoadAnnotations reads the annotations file of dataDir. A missing file
+// means no annotations yet, not an error.
+func LoadAnnotations(dataDir string) (Annotations, error) {
+	path := filepath.Join(dataDir, AnnotationsFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Annotations{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ann := Annotations{}
+	if err := json.Unmarshal(data, &ann); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return ann, nil
+}
+
+// Save writes the annotations to dataDir, replacing the file in one step so
+// an interrupted write can't leave it half written.
+func (a Annotations) Save(dataDir string) error {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(filepath.Join(dataDir, AnnotationsFile), append(data, '\n'), 0644)
+}
+
+// Flags returns the local flags of a trade, or none.
+func (a Annotations) Flags(tradeID int) []string {
+	return a[tradeID].Flags
+}
+
+// Flag adds flags to a trade and removes others, keeping the flags
+// lowercase, unique, and sorted. A trade left without flags is dropped.
+// It returns the trade's flags afterwards.
+func (a Annotations) Flag(tradeID int, add, remove []string) []string {
+	drop := make(map[string]bool, len(remove))
+	for _, f := range uniqueTags(remove) {
+		drop[f] = true
+	}
+
+	var flags []string
+	for _, f := range uniqueTags(append(a.Flags(tradeID), add...)) {
+		if !drop[f] {
+			flags = append(flags, f)
+		}
+	}
+	sort.Strings(flags)
+
+	if len(flags) == 0 {
+		delete(a, tradeID)
+		return nil
+	}
+	a[tradeID] = Annotation{Flags: flags, UpdatedAt: time.Now().UTC()}
+	return flags
+}
+
+// FilterFlags keeps the trades carrying any of flags in ann. Empty flags
+// means all trades.
+func FilterFlags(trades []models.Trade, ann Annotations, flags []string) []models.Trade {
+	if len(flags) == 0 {
+		return trades
+	}
+	want := make(map[string]bool, len(flags))
+	for _, f := range uniqueTags(flags) {
+		want[f] = true
+	}
+
+	var out []models.Trade
+	for _, t := range trades {
+		for _, f := range ann.Flags(t.ID) {
+			if want[f] {
+				out = append(out, t)
+				break
+			}
+		}
+	}
+	return out
+}
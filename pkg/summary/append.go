@@ -0,0 +1,120 @@
+package summary
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// AppendCSV adds to the CSV file at path the rows of summaries, sorted by
+// date, that are newer than its last row, so a rolling spreadsheet can be
+// kept up to date without rewriting it. A missing or empty file is written
+// whole, header included. Rows whose date is already in the file are never
+// written twice, and a file whose header differs from the one ro would
+// write (another --group-by or --fields, or --exposure added or dropped)
+// is refused. The rows must include the date column, wherever --fields
+// puts it, to tell which ones are new.
+// It returns the rows appended and the date of the file's last row before
+// them ("" for a new file).
+func (g *Generator) AppendCSV(path string, summaries []models.DailySummary, ro RenderOptions) (int, string, error) {
+	dateCol := slices.Index(ro.csvColumns(), 0)
+	if dateCol < 0 {
+		return 0, "", errors.New("appending needs the date column to tell which rows are new; add date to --fields")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, "", err
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		f, err := os.Create(path)
+		if err != nil {
+			return 0, "", err
+		}
+		if err := g.ExportCSV(f, summaries, ro); err != nil {
+			f.Close()
+			return 0, "", err
+		}
+		return len(summaries), "", f.Close()
+	}
+
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		return 0, "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	if header := ro.csvHeader(); !slices.Equal(records[0], header) {
+		return 0, "", fmt.Errorf("%s has the columns %s, but this run writes %s; append with the same --group-by, --fields, and --exposure it was written with",
+			path, strings.Join(records[0], ","), strings.Join(header, ","))
+	}
+
+	have := make(map[string]bool, len(records)-1)
+	last := ""
+	for i, rec := range records[1:] {
+		key, err := ro.dateKey(rec[dateCol])
+		if err != nil {
+			return 0, "", fmt.Errorf("%s line %d: %w", path, i+2, err)
+		}
+		have[key] = true
+		if key > last {
+			last = key
+		}
+	}
+
+	var rows []models.DailySummary
+	for _, s := range summaries {
+		if s.Date > last && !have[s.Date] {
+			rows = append(rows, s)
+		}
+	}
+	if len(rows) == 0 {
+		return 0, last, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return 0, last, err
+	}
+	// A file saved by hand may lack its final newline
+	if data[len(data)-1] != '\n' {
+		if _, err := io.WriteString(f, "\n"); err != nil {
+			f.Close()
+			return 0, last, err
+		}
+	}
+	cw := csv.NewWriter(f)
+	if err := ro.writeCSVRows(cw, rows); err != nil {
+		f.Close()
+		return 0, last, err
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		f.Close()
+		return 0, last, err
+	}
+	return len(rows), last, f.Close()
+}
+
+// dateKey turns a CSV date cell back into the yyyy-mm-dd form of
+// DailySummary.Date, undoing ro.DateLayout. Period labels (2026-W03,
+// 2026-01) are written as they are and come back unchanged.
+func (ro RenderOptions) dateKey(cell string) (string, error) {
+	if ro.Period != "" && ro.Period != PeriodDay {
+		return cell, nil
+	}
+	layout := ro.DateLayout
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+	t, err := time.Parse(layout, cell)
+	if err != nil {
+		return "", fmt.Errorf("date %q is not in the --date-format of this run", cell)
+	}
+	return t.Format("2006-01-02"), nil
+}
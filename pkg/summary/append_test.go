@@ -0,0 +1,108 @@
+package summary
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// daySummaries returns a summary with one trade for each date.
+func daySummaries(dates ...string) []models.DailySummary {
+	var out []models.DailySummary
+	for i, d := range dates {
+		out = append(out, models.DailySummary{Date: d, TradeCount: 1, GrossPL: float64(10 * (i + 1)), NetPL: float64(10 * (i + 1))})
+	}
+	return out
+}
+
+func TestAppendCSVFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []string
+		want   string
+	}{
+		{name: "date first", fields: []string{"date", "net_pl"},
+			want: "date,net_pl\n2025-01-02,10.00\n2025-01-03,20.00\n2025-01-06,30.00\n"},
+		{name: "date moved", fields: []string{"trades", "net_pl", "date"},
+			want: "trades,net_pl,date\n1,10.00,2025-01-02\n1,20.00,2025-01-03\n1,30.00,2025-01-06\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "daily.csv")
+			g := NewGenerator(t.TempDir())
+			g.SetLogger(logging.Discard)
+			ro := RenderOptions{Fields: tt.fields}
+
+			if n, _, err := g.AppendCSV(path, daySummaries("2025-01-02", "2025-01-03"), ro); err != nil || n != 2 {
+				t.Fatalf("first append = %d rows, %v; want 2 rows", n, err)
+			}
+			// The next run's range overlaps the rows already written
+			n, last, err := g.AppendCSV(path, daySummaries("2025-01-02", "2025-01-03", "2025-01-06"), ro)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 1 || last != "2025-01-03" {
+				t.Errorf("second append = %d rows after %q, want 1 row after 2025-01-03", n, last)
+			}
+			n, _, err = g.AppendCSV(path, daySummaries("2025-01-02", "2025-01-03"), ro)
+			if err != nil || n != 0 {
+				t.Errorf("re-appending old days = %d rows, %v; want none", n, err)
+			}
+
+			got, _ := os.ReadFile(path)
+			if string(got) != tt.want {
+				t.Errorf("file =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendCSVNeedsDate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daily.csv")
+	g := NewGenerator(t.TempDir())
+	_, _, err := g.AppendCSV(path, daySummaries("2025-01-02"), RenderOptions{Fields: []string{"trades", "net_pl"}})
+	if err == nil || !strings.Contains(err.Error(), "add date to --fields") {
+		t.Fatalf("AppendCSV without a date column = %v, want an error asking for date", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("AppendCSV wrote %s anyway", path)
+	}
+}
+
+func TestAppendCSVHeaderMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		ro       RenderOptions
+	}{
+		{name: "other fields", existing: "date,net_pl\n2025-01-02,10.00\n", ro: RenderOptions{Fields: []string{"date", "gross_pl"}}},
+		{name: "fields reordered", existing: "net_pl,date\n10.00,2025-01-02\n", ro: RenderOptions{Fields: []string{"date", "net_pl"}}},
+		{name: "grouped by week", existing: "date,net_pl\n2025-01-02,10.00\n", ro: RenderOptions{Period: PeriodWeek, Fields: []string{"date", "net_pl"}}},
+		{name: "exposure added", existing: "date,net_pl\n2025-01-02,10.00\n", ro: RenderOptions{Fields: []string{"date", "net_pl", "notional"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "daily.csv")
+			if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			g := NewGenerator(t.TempDir())
+			_, _, err := g.AppendCSV(path, daySummaries("2025-01-03"), tt.ro)
+			if err == nil {
+				t.Fatal("AppendCSV accepted a file with other columns")
+			}
+			want := "has the columns " + strings.SplitN(tt.existing, "\n", 2)[0] + ", but this run writes " + strings.Join(tt.ro.csvHeader(), ",")
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error = %q, want it to contain %q", err, want)
+			}
+			if got, _ := os.ReadFile(path); string(got) != tt.existing {
+				t.Errorf("file changed to %q", got)
+			}
+		})
+	}
+}
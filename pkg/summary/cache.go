@@ -0,0 +1,68 @@
+package summary
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// SummariesDir is the data subdirectory holding cached daily summaries.
+const SummariesDir = "summaries"
+
+// cacheVersion is bumped whenever buildDailySummary changes what it
+// computes, so summaries cached by an older version are recomputed.
+const cacheVersion = 5
+
+// cachedSummary is the on-disk form of a cached daily summary.
+type cachedSummary struct {
+	Version int                 `json:"version"`
+	Summary models.DailySummary `json:"summary"`
+}
+
+// Summarize computes the unfiltered summary of one day's trades, as
+// Generate would with default options.
+func Summarize(date string, trades []models.Trade) models.DailySummary {
+	return buildDailySummary(date, trades, Options{})
+}
+
+// CachePath returns where the cached summary for date is stored.
+func CachePath(dataDir, date string) string {
+	return filepath.Join(dataDir, SummariesDir, date+".json")
+}
+
+// MarshalCache encodes a summary for the cache.
+func MarshalCache(s models.DailySummary) ([]byte, error) {
+	return json.MarshalIndent(cachedSummary{Version: cacheVersion, Summary: s}, "", "  ")
+}
+
+// cacheable reports whether opts are the defaults the cache was built with.
+func (opts Options) cacheable() bool {
+	return len(opts.Symbols) == 0 && len(opts.ExcludeSymbols) == 0 && opts.Currency == "" &&
+		len(opts.Tags) == 0 && len(opts.Flags) == 0 && opts.ScratchThreshold == 0 && len(opts.Aliases) == 0 &&
+		opts.WinBasis != WinBasisNet
+}
+
+// loadCachedSummary returns the cached summary for a day file, if there is
+// one from this version that is at least as new as the day file.
+func (g *Generator) loadCachedSummary(f dayfile.File) (*models.DailySummary, bool) {
+	cachePath := CachePath(g.dataDir, f.Date)
+
+	cacheInfo, err := os.Stat(cachePath)
+	if err != nil || cacheInfo.ModTime().Before(f.ModTime) {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, false
+	}
+	var cached cachedSummary
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Version != cacheVersion {
+		return nil, false
+	}
+	cached.Summary.Date = f.Date
+	return &cached.Summary, true
+}
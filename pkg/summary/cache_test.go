@@ -0,0 +1,59 @@
+package summary
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+func TestStrictBypassesCache(t *testing.T) {
+	dir := t.TempDir()
+	const date = "2025-01-02"
+
+	// A day file copied under the wrong name, with an up-to-date cache
+	day := &models.DayExport{Date: "2025-01-03", Trades: []models.Trade{{ID: 1, Symbol: "AAPL", GrossPL: 10}}}
+	data, err := dayfile.Encode(day, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := dayfile.Path(dir, date, false)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := MarshalCache(Summarize(date, day.Trades))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cachePath := CachePath(dir, date)
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cachePath, cache, 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(cachePath, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGenerator(dir)
+	g.SetLogger(logging.Discard)
+	if summaries, err := g.Generate(Options{}); err != nil || len(summaries) != 1 {
+		t.Fatalf("non-strict Generate = %d summaries, %v; want 1 from the cache", len(summaries), err)
+	}
+
+	g.SetStrict(true)
+	_, err = g.Generate(Options{})
+	if err == nil || !strings.Contains(err.Error(), "holds date 2025-01-03") {
+		t.Fatalf("strict Generate = %v, want the day file's date mismatch", err)
+	}
+}
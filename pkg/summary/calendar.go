@@ -0,0 +1,74 @@
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// CalendarDay is one cell of a P&L calendar heatmap.
+type CalendarDay struct {
+	Date       string  `json:"date"`
+	NetPL      float64 `json:"net_pl"`
+	TradeCount int     `json:"trade_count"`
+}
+
+// calendarReport is the document written by ExportCalendar.
+type calendarReport struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	MinNetPL float64       `json:"min_net_pl"`
+	MaxNetPL float64       `json:"max_net_pl"`
+	Days     []CalendarDay `json:"days"`
+}
+
+// ExportCalendar writes daily summaries as a heatmap data file: one entry
+// per calendar day from from to to, with zero entries for days without
+// trades, plus the net P&L range for scaling colors. Empty from/to default
+// to the first and last summarized day.
+func (g *Generator) ExportCalendar(w io.Writer, summaries []models.DailySummary, from, to string) error {
+	byDate := make(map[string]models.DailySummary, len(summaries))
+	for _, s := range summaries {
+		byDate[s.Date] = s
+	}
+
+	if from == "" && len(summaries) > 0 {
+		from = summaries[0].Date
+	}
+	if to == "" && len(summaries) > 0 {
+		to = summaries[len(summaries)-1].Date
+	}
+
+	start, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return fmt.Errorf("invalid calendar start %q: %w", from, err)
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return fmt.Errorf("invalid calendar end %q: %w", to, err)
+	}
+
+	report := calendarReport{From: from, To: to, Days: []CalendarDay{}}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		day := CalendarDay{Date: key}
+		if s, ok := byDate[key]; ok {
+			day.NetPL = s.NetPL
+			day.TradeCount = s.TradeCount
+		}
+		if len(report.Days) == 0 || day.NetPL < report.MinNetPL {
+			report.MinNetPL = day.NetPL
+		}
+		if len(report.Days) == 0 || day.NetPL > report.MaxNetPL {
+			report.MaxNetPL = day.NetPL
+		}
+		report.Days = append(report.Days, day)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -0,0 +1,134 @@
+package summary
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// EquityPoint is cumulative net P&L at the end of one day (or period).
+type EquityPoint struct {
+	Date     string  `json:"date"`
+	NetPL    float64 `json:"net_pl"`   // the period's own net P&L
+	Equity   float64 `json:"equity"`   // net P&L summed up to and including Date
+	Drawdown float64 `json:"drawdown"` // Equity less the running peak; zero or negative
+}
+
+// EquityCurve is the running net P&L over a date range. The curve starts
+// from zero, so a losing first day is already a drawdown.
+type EquityCurve struct {
+	Points          []EquityPoint `json:"points"`
+	MaxDrawdown     float64       `json:"max_drawdown"`      // most negative Drawdown
+	MaxDrawdownDate string        `json:"max_drawdown_date"` // the trough, empty if never below the peak
+	PeakDate        string        `json:"peak_date"`         // the high before the trough
+}
+
+// Equity computes the equity curve of summaries, which must be sorted by
+// date.
+func Equity(summaries []models.DailySummary) EquityCurve {
+	var c EquityCurve
+	equity, peak := 0.0, 0.0
+	peakDate := ""
+
+	for _, s := range summaries {
+		equity += s.NetPL
+		if equity > peak {
+			peak, peakDate = equity, s.Date
+		}
+		p := EquityPoint{Date: s.Date, NetPL: s.NetPL, Equity: equity, Drawdown: equity - peak}
+		c.Points = append(c.Points, p)
+
+		if p.Drawdown < c.MaxDrawdown {
+			c.MaxDrawdown = p.Drawdown
+			c.MaxDrawdownDate = s.Date
+			c.PeakDate = peakDate
+		}
+	}
+	return c
+}
+
+// SymbolEquity computes an equity curve per symbol from the per-symbol
+// rows of summaries, which must be sorted by date. Each curve has a point
+// for every day its symbol traded, so Drawdown is measured between those
+// days. Symbols traded on fewer than minDays days are left out.
+func SymbolEquity(summaries []models.DailySummary, minDays int) map[string]EquityCurve {
+	days := make(map[string][]models.DailySummary)
+	for _, s := range summaries {
+		for _, sym := range s.Symbols {
+			days[sym.Symbol] = append(days[sym.Symbol], models.DailySummary{Date: s.Date, NetPL: sym.NetPL})
+		}
+	}
+
+	curves := make(map[string]EquityCurve)
+	for sym, d := range days {
+		if len(d) >= minDays {
+			curves[sym] = Equity(d)
+		}
+	}
+	return curves
+}
+
+// PrintEquity prints the equity curve as a table, with the maximum
+// drawdown underneath.
+func (g *Generator) PrintEquity(w io.Writer, c EquityCurve, ro RenderOptions) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := strings.ToUpper(ro.dateHeader())
+	fmt.Fprintf(tw, "%s\tNET P&L\tEQUITY\tDRAWDOWN\n", header)
+	fmt.Fprintf(tw, "%s\t───────\t──────\t────────\n", strings.Repeat("─", len(header)))
+	for _, p := range c.Points {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", ro.formatDate(p.Date), FormatPL(p.NetPL), FormatPL(p.Equity), FormatPL(p.Drawdown))
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, equityTrailer(c, ro))
+}
+
+// ExportEquityCSV writes the equity curve as CSV. The last row holds the
+// maximum drawdown: "max_drawdown", its date, and the amount.
+func (g *Generator) ExportEquityCSV(w io.Writer, c EquityCurve, ro RenderOptions) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	if err := cw.Write([]string{ro.dateHeader(), "net_pl", "equity", "drawdown"}); err != nil {
+		return err
+	}
+	for _, p := range c.Points {
+		if err := cw.Write([]string{
+			ro.formatDate(p.Date),
+			fmt.Sprintf("%.2f", p.NetPL),
+			fmt.Sprintf("%.2f", p.Equity),
+			fmt.Sprintf("%.2f", p.Drawdown),
+		}); err != nil {
+			return err
+		}
+	}
+	return cw.Write([]string{"max_drawdown", ro.formatDate(c.MaxDrawdownDate), "", fmt.Sprintf("%.2f", c.MaxDrawdown)})
+}
+
+// ExportEquityJSON writes the equity curve as an indented JSON document.
+func (g *Generator) ExportEquityJSON(w io.Writer, c EquityCurve) error {
+	if c.Points == nil {
+		c.Points = []EquityPoint{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+func equityTrailer(c EquityCurve, ro RenderOptions) string {
+	if c.MaxDrawdownDate == "" {
+		return "Max drawdown: none (equity never fell below its peak)"
+	}
+	from := "the start"
+	if c.PeakDate != "" {
+		from = "peak on " + ro.formatDate(c.PeakDate)
+	}
+	return fmt.Sprintf("Max drawdown: %s on %s (from %s)", FormatPL(c.MaxDrawdown), ro.formatDate(c.MaxDrawdownDate), from)
+}
@@ -0,0 +1,37 @@
+package summary
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// exposureHeaders are the column headers ro.Exposure adds after volume.
+var exposureHeaders = []string{"NOTIONAL", "MAX POS"}
+
+// exposure returns the notional cells of s when ro.Exposure is set, or
+// none.
+func (ro RenderOptions) exposure(s models.DailySummary) []string {
+	if !ro.Exposure {
+		return nil
+	}
+	return []string{ro.notional(s.TotalNotional), ro.notional(s.MaxPositionNotional)}
+}
+
+// notional renders an unsigned dollar amount, or redaction units when
+// ro.Redacted is set.
+func (ro RenderOptions) notional(v float64) string {
+	if ro.Redacted {
+		return fmt.Sprintf("%.2fu", v)
+	}
+	return fmt.Sprintf("$%.2f", v)
+}
+
+// tabCells joins cells for a tabwriter row, each preceded by a tab.
+func tabCells(cells []string) string {
+	if len(cells) == 0 {
+		return ""
+	}
+	return "\t" + strings.Join(cells, "\t")
+}
@@ -0,0 +1,76 @@
+package summary
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// csvFields names the CSV columns in their default order. The first is
+// the date, headed by the --group-by period when rows are rolled up, and
+// the last two are only written by default with RenderOptions.Exposure.
+// Columns are added after symbols, so the original eleven keep their
+// positions for sheets that read them by position.
+var csvFields = []string{
+	"date", "trades", "gross_pl", "net_pl", "commission", "fees",
+	"win_rate", "winners", "losers", "volume", "symbols",
+	"avg_r", "scratches", "open", "currencies", "avg_hold_seconds", "intraday", "multiday",
+	"notional", "max_position_notional",
+}
+
+// exposureFields is how many of csvFields, at the end, are exposure
+// columns.
+const exposureFields = 2
+
+// CSVFields returns the column names RenderOptions.Fields may pick from.
+func CSVFields() []string {
+	return slices.Clone(csvFields)
+}
+
+// ParseFields reads a comma-separated --fields list, such as
+// "date,net_pl,win_rate". Names are case-insensitive and may not repeat;
+// an unknown one is an error listing the valid names.
+func ParseFields(s string) ([]string, error) {
+	var fields []string
+	for _, f := range strings.Split(s, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		if !slices.Contains(csvFields, f) {
+			return nil, fmt.Errorf("unknown field %q (valid fields: %s)", f, strings.Join(csvFields, ", "))
+		}
+		if slices.Contains(fields, f) {
+			return nil, fmt.Errorf("field %q given twice", f)
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields given (valid fields: %s)", strings.Join(csvFields, ", "))
+	}
+	return fields, nil
+}
+
+// csvColumns is the indexes into csvFields of the columns ExportCSV
+// writes, in order.
+func (ro RenderOptions) csvColumns() []int {
+	if len(ro.Fields) > 0 {
+		cols := make([]int, 0, len(ro.Fields))
+		for _, f := range ro.Fields {
+			if i := slices.Index(csvFields, f); i >= 0 {
+				cols = append(cols, i)
+			}
+		}
+		return cols
+	}
+
+	n := len(csvFields)
+	if !ro.Exposure {
+		n -= exposureFields
+	}
+	cols := make([]int, n)
+	for i := range cols {
+		cols[i] = i
+	}
+	return cols
+}
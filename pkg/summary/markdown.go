@@ -0,0 +1,84 @@
+package summary
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// ExportMarkdown writes summaries as a GitHub-flavored Markdown table with
+// the same columns as PrintTable, ending in a bold totals row.
+func (g *Generator) ExportMarkdown(w io.Writer, summaries []models.DailySummary, ro RenderOptions) error {
+	bw := bufio.NewWriter(w)
+
+	row := func(cells ...string) {
+		for i, c := range cells {
+			cells[i] = strings.ReplaceAll(c, "|", `\|`)
+		}
+		fmt.Fprintf(bw, "| %s |\n", strings.Join(cells, " | "))
+	}
+
+	header := ro.dateHeader()
+	cols := []string{strings.ToUpper(header[:1]) + header[1:], "Trades", "Gross P&L", "Net P&L", "Win%", "Scr", "Avg R", "Avg Hold", "Volume"}
+	if ro.Exposure {
+		cols = append(cols, "Notional", "Max Pos")
+	}
+	cols = append(cols, "Symbols")
+	row(cols...)
+	fmt.Fprintln(bw, "|"+strings.Repeat("---|", len(cols)))
+
+	for _, s := range summaries {
+		date := ro.formatDate(s.Date)
+		if s.OpenCount > 0 {
+			date += "\\*"
+		}
+		cells := []string{
+			date,
+			fmt.Sprintf("%d", s.TradeCount),
+			ro.money(s.GrossPL),
+			ro.money(s.NetPL),
+			fmt.Sprintf("%.0f%%", s.WinRate),
+			fmt.Sprintf("%d", s.Scratches),
+			formatR(s.AvgRMultiple),
+			formatHold(s.AvgHoldSeconds, s.HeldTrades),
+			fmt.Sprintf("%d", s.TotalVolume),
+		}
+		cells = append(cells, ro.exposure(s)...)
+		row(append(cells, ro.formatSymbols(s.Symbols))...)
+	}
+
+	tot, periods := ro.totals(summaries)
+	cells := []string{
+		"**TOTAL**",
+		fmt.Sprintf("**%d**", tot.TradeCount),
+		"**" + ro.money(tot.GrossPL) + "**",
+		"**" + ro.money(tot.NetPL) + "**",
+		fmt.Sprintf("**%.0f%%**", tot.WinRate),
+		fmt.Sprintf("**%d**", tot.Scratches),
+		"**" + formatR(tot.AvgRMultiple) + "**",
+		"**" + formatHold(tot.AvgHoldSeconds, tot.HeldTrades) + "**",
+		fmt.Sprintf("**%d**", tot.TotalVolume),
+	}
+	for _, c := range ro.exposure(tot) {
+		cells = append(cells, "**"+c+"**")
+	}
+	row(append(cells, fmt.Sprintf("**%d %s**", periods, ro.Period.plural()))...)
+
+	// Separate subtotals, since P&L in different currencies can't be added up
+	if len(tot.Currencies) > 1 {
+		for _, cp := range tot.Currencies {
+			sub := make([]string, len(cols))
+			sub[0], sub[1], sub[2] = cp.Currency, fmt.Sprintf("%d", cp.Count), formatNative(cp.GrossPL)
+			row(sub...)
+		}
+	}
+
+	if tot.OpenCount > 0 {
+		fmt.Fprintf(bw, "\n\\* %s\n", tot.UnrealizedNote)
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,64 @@
+package summary
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WithNotes returns the rows whose trade has non-blank notes, in order.
+func WithNotes(rows []DayTrade) []DayTrade {
+	var out []DayTrade
+	for _, r := range rows {
+		if strings.TrimSpace(r.Trade.Notes) != "" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// ExportNotesMarkdown writes the notes of rows as a Markdown document: one
+// section per day, and under it one entry per trade headed by its symbol,
+// side, and net P&L. Trades with blank notes are skipped. It returns how
+// many trades' notes were written.
+func (g *Generator) ExportNotesMarkdown(w io.Writer, rows []DayTrade) (int, error) {
+	bw := bufio.NewWriter(w)
+	rows = WithNotes(rows)
+
+	fmt.Fprintln(bw, "# Trade Notes")
+	if len(rows) > 0 {
+		noun := "trades"
+		if len(rows) == 1 {
+			noun = "trade"
+		}
+		fmt.Fprintf(bw, "\n%d %s with notes, %s to %s.\n", len(rows), noun, rows[0].Date, rows[len(rows)-1].Date)
+	}
+
+	day := ""
+	for _, r := range rows {
+		t := r.Trade
+		if r.Date != day {
+			day = r.Date
+			fmt.Fprintf(bw, "\n## %s\n", day)
+		}
+
+		pl := FormatPL(tradeNetPL(t))
+		if t.Open {
+			pl = "open"
+		}
+		fmt.Fprintf(bw, "\n### %s, %s (trade %d)\n\n", strings.TrimSpace(t.Symbol+" "+t.Side), pl, t.ID)
+		if len(t.Tags) > 0 {
+			fmt.Fprintf(bw, "Tags: %s\n\n", strings.Join(t.Tags, ", "))
+		}
+		fmt.Fprintln(bw, strings.TrimSpace(t.Notes))
+		switch {
+		case t.CommentCount == 1:
+			fmt.Fprintln(bw, "\n_1 comment on Tradervue_")
+		case t.CommentCount > 1:
+			fmt.Fprintf(bw, "\n_%d comments on Tradervue_\n", t.CommentCount)
+		}
+	}
+
+	return len(rows), bw.Flush()
+}
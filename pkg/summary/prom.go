@@ -0,0 +1,60 @@
+package summary
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// promMetric is one gauge in the Prometheus output, with a sample per row.
+type promMetric struct {
+	name  string
+	help  string
+	value func(models.DailySummary) float64
+}
+
+var promMetrics = []promMetric{
+	{"tvue_net_pl", "Net P&L after commission and fees", func(s models.DailySummary) float64 { return s.NetPL }},
+	{"tvue_gross_pl", "Gross P&L of closed trades", func(s models.DailySummary) float64 { return s.GrossPL }},
+	{"tvue_fees", "Commission and fees paid", func(s models.DailySummary) float64 { return s.Commission + s.Fees }},
+	{"tvue_trades", "Trades, open ones included", func(s models.DailySummary) float64 { return float64(s.TradeCount) }},
+	{"tvue_winners", "Winning closed trades", func(s models.DailySummary) float64 { return float64(s.Winners) }},
+	{"tvue_losers", "Losing closed trades", func(s models.DailySummary) float64 { return float64(s.Losers) }},
+	{"tvue_win_rate", "Percent of closed trades that won (scratches excluded)", func(s models.DailySummary) float64 { return s.WinRate }},
+	{"tvue_volume", "Shares traded", func(s models.DailySummary) float64 { return float64(s.TotalVolume) }},
+}
+
+// ExportProm writes summaries in the Prometheus text format, for the
+// node_exporter textfile collector: a gauge per metric with one sample per
+// row, labeled by its date (or period label after a rollup), followed by
+// totals over all rows without labels.
+func (g *Generator) ExportProm(w io.Writer, summaries []models.DailySummary, ro RenderOptions) error {
+	bw := bufio.NewWriter(w)
+	total, _ := ro.totals(summaries)
+
+	for _, m := range promMetrics {
+		fmt.Fprintf(bw, "# HELP %s %s, by date.\n", m.name, m.help)
+		fmt.Fprintf(bw, "# TYPE %s gauge\n", m.name)
+		for _, s := range summaries {
+			fmt.Fprintf(bw, "%s{date=%q} %s\n", m.name, s.Date, promValue(m.value(s)))
+		}
+	}
+
+	for _, m := range promMetrics {
+		name := "tvue_total_" + strings.TrimPrefix(m.name, "tvue_")
+		fmt.Fprintf(bw, "# HELP %s %s, over all rows.\n", name, m.help)
+		fmt.Fprintf(bw, "# TYPE %s gauge\n", name)
+		fmt.Fprintf(bw, "%s %s\n", name, promValue(m.value(total)))
+	}
+
+	return bw.Flush()
+}
+
+// promValue formats a sample value as Prometheus expects.
+func promValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
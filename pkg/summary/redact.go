@@ -0,0 +1,78 @@
+package summary
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// RedactionUnit is the mean absolute net P&L of the rows with closed
+// trades, the amount Redact scales to 1. It is 1 when there are none, or
+// all broke even.
+func RedactionUnit(rows []models.DailySummary) float64 {
+	sum, n := 0.0, 0
+	for _, s := range rows {
+		if s.TradeCount > s.OpenCount {
+			sum += math.Abs(s.NetPL)
+			n++
+		}
+	}
+	if n == 0 || sum == 0 {
+		return 1
+	}
+	return sum / float64(n)
+}
+
+// Redact returns a copy of rows with every dollar amount divided by unit,
+// so results can be shared without revealing account size: ratios between
+// amounts, win rates, counts, symbols, and R-multiples are unchanged.
+// Render the result with RenderOptions.Redacted set.
+func Redact(rows []models.DailySummary, unit float64) []models.DailySummary {
+	if rows == nil {
+		return nil
+	}
+	out := make([]models.DailySummary, len(rows))
+	for i, s := range rows {
+		s.GrossPL /= unit
+		s.NetPL /= unit
+		s.Commission /= unit
+		s.Fees /= unit
+		s.TotalNotional /= unit
+		s.MaxPositionNotional /= unit
+		if s.AvgMAE != nil {
+			mae := *s.AvgMAE / unit
+			s.AvgMAE = &mae
+		}
+
+		syms := make([]models.SymbolSummary, len(s.Symbols))
+		for j, sym := range s.Symbols {
+			sym.GrossPL /= unit
+			sym.Commission /= unit
+			sym.Fees /= unit
+			sym.NetPL /= unit
+			syms[j] = sym
+		}
+		s.Symbols = syms
+
+		if s.Currencies != nil {
+			cur := make([]models.CurrencyPL, len(s.Currencies))
+			for j, c := range s.Currencies {
+				c.GrossPL /= unit
+				cur[j] = c
+			}
+			s.Currencies = cur
+		}
+		out[i] = s
+	}
+	return out
+}
+
+// money renders an amount as FormatPL does, or in redaction units, e.g.
+// +1.25u, when ro.Redacted is set.
+func (ro RenderOptions) money(v float64) string {
+	if ro.Redacted {
+		return fmt.Sprintf("%+.2fu", v)
+	}
+	return FormatPL(v)
+}
@@ -0,0 +1,209 @@
+package summary
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// Period is the bucket size summaries are grouped by.
+type Period string
+
+const (
+	PeriodDay   Period = "day"
+	PeriodWeek  Period = "week"
+	PeriodMonth Period = "month"
+	PeriodYear  Period = "year"
+)
+
+// ParsePeriod validates a --group-by value. An empty string means per-day.
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(strings.ToLower(s)); p {
+	case "":
+		return PeriodDay, nil
+	case PeriodDay, PeriodWeek, PeriodMonth, PeriodYear:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid grouping %q (use day, week, month, or year)", s)
+}
+
+// label returns the bucket label for a yyyy-mm-dd date, e.g. "2025-W03",
+// "2025-01", or "2025".
+func (p Period) label(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+
+	switch p {
+	case PeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case PeriodMonth:
+		return t.Format("2006-01")
+	case PeriodYear:
+		return t.Format("2006")
+	}
+	return date
+}
+
+// FitsIn reports whether buckets of p never straddle two of q, so rows
+// grouped by p can be split into files by q. Weeks only fit in weeks.
+func (p Period) FitsIn(q Period) bool {
+	switch p {
+	case PeriodDay, "":
+		return true
+	case PeriodMonth:
+		return q == PeriodMonth || q == PeriodYear
+	}
+	return p == q
+}
+
+// Part is the daily summaries of one period, e.g. one month's days.
+type Part struct {
+	Label     string // the period label, as Rollup would give it
+	Summaries []models.DailySummary
+}
+
+// Split divides daily summaries, sorted by date, into one part per period.
+func Split(summaries []models.DailySummary, p Period) []Part {
+	var parts []Part
+	for _, s := range summaries {
+		label := p.label(s.Date)
+		if n := len(parts); n > 0 && parts[n-1].Label == label {
+			parts[n-1].Summaries = append(parts[n-1].Summaries, s)
+			continue
+		}
+		parts = append(parts, Part{Label: label, Summaries: []models.DailySummary{s}})
+	}
+	return parts
+}
+
+// plural names a count of buckets, as used in the totals row.
+func (p Period) plural() string {
+	if p == "" {
+		return "days"
+	}
+	return string(p) + "s"
+}
+
+// Rollup aggregates daily summaries into coarser buckets. The input must be
+// sorted by date; each bucket's Date is its period label. Per-day input is
+// returned unchanged.
+func Rollup(summaries []models.DailySummary, p Period) []models.DailySummary {
+	if p == "" || p == PeriodDay {
+		return summaries
+	}
+
+	var out []models.DailySummary
+	var bucket []models.DailySummary
+	current := ""
+
+	for _, s := range summaries {
+		label := p.label(s.Date)
+		if label != current && len(bucket) > 0 {
+			out = append(out, mergeSummaries(current, bucket))
+			bucket = nil
+		}
+		current = label
+		bucket = append(bucket, s)
+	}
+	if len(bucket) > 0 {
+		out = append(out, mergeSummaries(current, bucket))
+	}
+
+	return out
+}
+
+// mergeSummaries sums a set of summaries into one, recomputing win rate and
+// combining per-symbol rows.
+func mergeSummaries(label string, summaries []models.DailySummary) models.DailySummary {
+	m := models.DailySummary{Date: label}
+
+	symIndex := make(map[string]int)
+	currencies := make(map[string]*models.CurrencyPL)
+	var rSum, holdSum, captureSum, maeSum float64
+
+	for _, s := range summaries {
+		m.TradeCount += s.TradeCount
+		m.GrossPL += s.GrossPL
+		m.NetPL += s.NetPL
+		m.Commission += s.Commission
+		m.Fees += s.Fees
+		m.TotalVolume += s.TotalVolume
+		m.TotalNotional += s.TotalNotional
+		m.MaxPositionNotional = math.Max(m.MaxPositionNotional, s.MaxPositionNotional)
+		m.Winners += s.Winners
+		m.Losers += s.Losers
+		m.Scratches += s.Scratches
+		m.OpenCount += s.OpenCount
+		m.IntradayCount += s.IntradayCount
+		m.MultidayCount += s.MultidayCount
+		holdSum += s.AvgHoldSeconds * float64(s.HeldTrades)
+		m.HeldTrades += s.HeldTrades
+		if s.AvgRMultiple != nil {
+			rSum += *s.AvgRMultiple * float64(s.RiskedTrades)
+			m.RiskedTrades += s.RiskedTrades
+		}
+		if s.AvgCaptureRatio != nil {
+			captureSum += *s.AvgCaptureRatio * float64(s.CaptureTrades)
+			m.CaptureTrades += s.CaptureTrades
+		}
+		if s.AvgMAE != nil {
+			maeSum += *s.AvgMAE * float64(s.MAETrades)
+			m.MAETrades += s.MAETrades
+		}
+
+		for _, c := range s.Currencies {
+			cp, ok := currencies[c.Currency]
+			if !ok {
+				cp = &models.CurrencyPL{Currency: c.Currency}
+				currencies[c.Currency] = cp
+			}
+			cp.GrossPL += c.GrossPL
+			cp.Count += c.Count
+		}
+
+		for _, sym := range s.Symbols {
+			i, ok := symIndex[sym.Symbol]
+			if !ok {
+				symIndex[sym.Symbol] = len(m.Symbols)
+				m.Symbols = append(m.Symbols, sym)
+				continue
+			}
+			agg := &m.Symbols[i]
+			switch {
+			case agg.Side == "":
+				agg.Side = sym.Side
+			case sym.Side != "" && agg.Side != sym.Side:
+				agg.Side = "L/S"
+			}
+			agg.GrossPL += sym.GrossPL
+			agg.Commission += sym.Commission
+			agg.Fees += sym.Fees
+			agg.NetPL += sym.NetPL
+			agg.Volume += sym.Volume
+			agg.Count += sym.Count
+		}
+	}
+
+	if m.Winners+m.Losers > 0 {
+		m.WinRate = float64(m.Winners) / float64(m.Winners+m.Losers) * 100
+	}
+	if m.RiskedTrades > 0 {
+		avg := rSum / float64(m.RiskedTrades)
+		m.AvgRMultiple = &avg
+	}
+	m.AvgCaptureRatio = average(captureSum, m.CaptureTrades)
+	m.AvgMAE = average(maeSum, m.MAETrades)
+	m.UnrealizedNote = unrealizedNote(m.OpenCount, nil)
+	if m.HeldTrades > 0 {
+		m.AvgHoldSeconds = holdSum / float64(m.HeldTrades)
+	}
+	m.Currencies = sortedCurrencies(currencies)
+
+	return m
+}
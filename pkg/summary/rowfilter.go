@@ -0,0 +1,38 @@
+package summary
+
+import "github.com/jefrnc/tradervue-utils/pkg/models"
+
+// RowFilter hides summary rows outside thresholds, after aggregation. Nil
+// bounds and a zero MinTrades don't filter.
+type RowFilter struct {
+	MinTrades int
+	MinNetPL  *float64
+	MaxNetPL  *float64
+}
+
+// Active reports whether f filters anything.
+func (f RowFilter) Active() bool {
+	return f.MinTrades > 0 || f.MinNetPL != nil || f.MaxNetPL != nil
+}
+
+// Apply returns the summaries that meet every threshold in f.
+func (f RowFilter) Apply(summaries []models.DailySummary) []models.DailySummary {
+	if !f.Active() {
+		return summaries
+	}
+
+	var out []models.DailySummary
+	for _, s := range summaries {
+		if s.TradeCount < f.MinTrades {
+			continue
+		}
+		if f.MinNetPL != nil && s.NetPL < *f.MinNetPL {
+			continue
+		}
+		if f.MaxNetPL != nil && s.NetPL > *f.MaxNetPL {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package summary
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// snippetContext is how many characters of note text a search snippet shows
+// on each side of the match.
+const snippetContext = 40
+
+// SearchMatch is a trade whose notes matched a search, with the matching
+// part of the notes.
+type SearchMatch struct {
+	DayTrade
+	Snippet string
+}
+
+// SearchPattern compiles a case-insensitive pattern for Search. The query is
+// matched literally unless regex is set.
+func SearchPattern(query string, regex bool) (*regexp.Regexp, error) {
+	if !regex {
+		query = regexp.QuoteMeta(query)
+	}
+	if _, err := regexp.Compile(query); err != nil {
+		return nil, fmt.Errorf("invalid --regex pattern: %w", err)
+	}
+	return regexp.MustCompile("(?i)" + query), nil
+}
+
+// Search returns the rows whose notes (or, failing that, notes excerpt)
+// match re, in the order given.
+func Search(rows []DayTrade, re *regexp.Regexp) []SearchMatch {
+	var matches []SearchMatch
+	for _, r := range rows {
+		for _, text := range []string{r.Trade.Notes, r.Trade.NotesExcerpt} {
+			if snippet, ok := matchSnippet(text, re); ok {
+				matches = append(matches, SearchMatch{DayTrade: r, Snippet: snippet})
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// matchSnippet finds re in text, with line breaks and runs of spaces folded
+// to single spaces, and returns the first match with some text around it.
+func matchSnippet(text string, re *regexp.Regexp) (string, bool) {
+	text = strings.Join(strings.Fields(text), " ")
+	loc := re.FindStringIndex(text)
+	if loc == nil {
+		return "", false
+	}
+
+	start, end := loc[0], loc[1]
+	for i := 0; i < snippetContext && start > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(text[:start])
+		start -= size
+	}
+	for i := 0; i < snippetContext && end < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+
+	snippet := text[start:end]
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet, true
+}
+
+// PrintSearch prints search matches as a formatted ASCII table.
+func (g *Generator) PrintSearch(w io.Writer, matches []SearchMatch) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "DATE\tID\tSYMBOL\tNET P&L\tNOTES\n")
+	fmt.Fprintf(tw, "────\t──\t──────\t───────\t─────\n")
+
+	for _, m := range matches {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n",
+			m.Date,
+			m.Trade.ID,
+			m.Trade.Symbol,
+			FormatPL(tradeNetPL(m.Trade)),
+			m.Snippet,
+		)
+	}
+
+	tw.Flush()
+}
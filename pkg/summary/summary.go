@@ -0,0 +1,917 @@
+// Package summary reads an exported data directory and computes daily
+// summaries, trade listings, and per-tag breakdowns, with renderers for
+// tables, CSV, and JSON.
+package summary
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/internal/dayfile"
+	"github.com/jefrnc/tradervue-utils/pkg/logging"
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// Options controls which trades are included in the summaries.
+type Options struct {
+	FromDate string   // yyyy-mm-dd, empty means no lower bound
+	ToDate   string   // yyyy-mm-dd, empty means no upper bound
+	Symbols  []string // only include these symbols; empty means all
+
+	// ExcludeSymbols drops these symbols after Symbols is applied, so a
+	// symbol in both lists is excluded.
+	ExcludeSymbols []string
+
+	// ScratchThreshold is the largest absolute P&L, on WinBasis, that still
+	// counts as a break-even (scratch) trade. Zero means only exactly $0.00.
+	ScratchThreshold float64
+
+	// WinBasis is the P&L that decides winners and losers: gross (the
+	// default) or net of commission and fees.
+	WinBasis WinBasis
+
+	// Currency keeps only trades made in this currency (e.g. USD); empty
+	// means all. Trades without a native currency are in DefaultCurrency.
+	Currency string
+
+	// Tags keeps only trades carrying these tags: any of them, or all of
+	// them when MatchAllTags is set. Empty means all trades.
+	Tags         []string
+	MatchAllTags bool
+
+	// Aliases maps old symbols to the ones they became (see ParseAliases),
+	// applied before the symbol filters so renamed tickers count as one.
+	Aliases map[string]string
+
+	// Annotations are the local trade annotations (see LoadAnnotations);
+	// Flags keeps only trades annotated with any of these flags. Empty
+	// Flags means all trades.
+	Annotations Annotations
+	Flags       []string
+}
+
+// filter applies the trade-level filters in opts. It works on a copy, so
+// the caller's trades keep their symbols as exported.
+func (opts Options) filter(trades []models.Trade) []models.Trade {
+	trades = slices.Clone(trades)
+	models.NormalizeSymbols(trades)
+	ApplyAliases(trades, opts.Aliases)
+	trades = models.FilterSymbols(trades, opts.Symbols)
+	trades = models.ExcludeSymbols(trades, opts.ExcludeSymbols)
+	trades = FilterCurrency(trades, opts.Currency)
+	trades = FilterFlags(trades, opts.Annotations, opts.Flags)
+	return models.FilterTags(trades, opts.Tags, opts.MatchAllTags)
+}
+
+// DefaultCurrency is the currency of trades that carry no NativeCurrency,
+// i.e. the account's own currency.
+const DefaultCurrency = "USD"
+
+// RenderOptions controls how summaries are rendered.
+type RenderOptions struct {
+	Period     Period // grouping the summaries were rolled up by (default: day)
+	DateLayout string // Go time layout for dates in tables and CSV (default: yyyy-mm-dd)
+
+	// TotalsOver, when set, is what the totals row sums instead of the rows
+	// shown, e.g. every day when RowFilter has hidden some of them.
+	TotalsOver []models.DailySummary
+
+	// Redacted marks amounts as in units of RedactionUnit rather than
+	// dollars (see Redact), so tables show them as +1.25u.
+	Redacted bool
+
+	// Exposure adds the TotalNotional and MaxPositionNotional columns to
+	// tables and CSV.
+	Exposure bool
+
+	// Fields picks and orders the CSV columns by name (see CSVFields),
+	// overriding Exposure there. Empty writes every column.
+	Fields []string
+}
+
+// totals is the totals row for rows and how many periods it covers.
+func (ro RenderOptions) totals(rows []models.DailySummary) (models.DailySummary, int) {
+	if ro.TotalsOver != nil {
+		rows = ro.TotalsOver
+	}
+	return mergeSummaries("TOTAL", rows), len(rows)
+}
+
+// formatDate renders a yyyy-mm-dd date in ro.DateLayout. Week, month, and
+// year labels aren't dates and are returned unchanged.
+func (ro RenderOptions) formatDate(date string) string {
+	if ro.DateLayout == "" {
+		return date
+	}
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.Format(ro.DateLayout)
+}
+
+// dateFormatPresets are the named --date-format values.
+var dateFormatPresets = map[string]string{
+	"iso": "2006-01-02",
+	"us":  "01/02/2006",
+	"eu":  "02/01/2006",
+}
+
+// ParseDateFormat resolves a --date-format value, either a preset name (iso,
+// us, eu) or a Go time layout, to a layout. A layout must keep the year,
+// month, and day, so that every date still reads back unambiguously.
+func ParseDateFormat(s string) (string, error) {
+	if s == "" {
+		return "2006-01-02", nil
+	}
+	if layout, ok := dateFormatPresets[strings.ToLower(s)]; ok {
+		return layout, nil
+	}
+
+	// A layout without a year, month, or day doesn't round-trip
+	ref := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+	back, err := time.Parse(s, ref.Format(s))
+	if err != nil || !back.Equal(ref) {
+		return "", fmt.Errorf("invalid date format %q (use iso, us, eu, or a Go layout such as 01/02/2006)", s)
+	}
+	return s, nil
+}
+
+// dateHeader is the label of the first column for the grouping.
+func (ro RenderOptions) dateHeader() string {
+	if ro.Period == "" || ro.Period == PeriodDay {
+		return "date"
+	}
+	return string(ro.Period)
+}
+
+// Generator reads exported day files and produces summaries.
+type Generator struct {
+	dataDir string
+	logger  logging.Logger // warnings about the data directory; text on stderr by default
+
+	loc    *time.Location // zone trades were grouped into days by; nil skips the trade date check
+	strict bool           // day files that disagree with their filename are errors, not warnings
+}
+
+// NewGenerator creates a new summary generator. It warns as text on stderr
+// until SetLogger says otherwise.
+func NewGenerator(dataDir string) *Generator {
+	return &Generator{dataDir: dataDir, logger: logging.NewText(os.Stderr)}
+}
+
+// SetLogger sends the generator's warnings to l. Use logging.Discard to
+// silence them.
+func (g *Generator) SetLogger(l logging.Logger) {
+	if l == nil {
+		l = logging.NewText(os.Stderr)
+	}
+	g.logger = l
+}
+
+// SetTimezone has the generator check that each trade's start time, read
+// in loc, falls on the date of the day file holding it. loc should be the
+// zone the data was exported with.
+func (g *Generator) SetTimezone(loc *time.Location) {
+	g.loc = loc
+}
+
+// SetStrict makes a day file whose contents disagree with its filename an
+// error instead of a warning.
+func (g *Generator) SetStrict(strict bool) {
+	g.strict = strict
+}
+
+// Generate produces daily summaries for the date range and symbols in opts.
+// When a symbol filter is set, each day is aggregated from the matching
+// trades only, and days with no matching trades are omitted.
+//
+// Unfiltered runs use the cached summary written by "export --with-summary"
+// for any day whose cache is up to date, without parsing its day file.
+// Strict runs read every day file, since the cache can't be checked
+// against its filename.
+func (g *Generator) Generate(opts Options) ([]models.DailySummary, error) {
+	files, err := g.dayFiles(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	useCache := opts.cacheable() && !g.strict
+	var summaries []models.DailySummary
+
+	for _, f := range files {
+		if useCache {
+			if cached, ok := g.loadCachedSummary(f); ok {
+				if cached.TradeCount > 0 {
+					summaries = append(summaries, *cached)
+				}
+				continue
+			}
+		}
+
+		day, err := dayfile.Load(f.Path)
+		if err != nil {
+			continue
+		}
+		if err := g.checkDay(f, day); err != nil {
+			return nil, err
+		}
+		trades := opts.filter(day.Trades)
+
+		// Journal-only days (or days without matching trades) have nothing to summarize
+		if len(trades) == 0 {
+			continue
+		}
+
+		summaries = append(summaries, buildDailySummary(f.Date, trades, opts))
+	}
+
+	return summaries, nil
+}
+
+// Days loads the day files within the date range in opts, sorted by date.
+// The Date of each result is taken from its filename. Unreadable files are
+// skipped.
+func (g *Generator) Days(opts Options) ([]*models.DayExport, error) {
+	files, err := g.dayFiles(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []*models.DayExport
+	for _, f := range files {
+		dayExport, err := dayfile.Load(f.Path)
+		if err != nil {
+			continue
+		}
+		if err := g.checkDay(f, dayExport); err != nil {
+			return nil, err
+		}
+		dayExport.Date = f.Date
+
+		days = append(days, dayExport)
+	}
+
+	return days, nil
+}
+
+// dayFiles lists the day files within the date range in opts, sorted by date.
+func (g *Generator) dayFiles(opts Options) ([]dayfile.File, error) {
+	if err := validateRange(opts.FromDate, opts.ToDate); err != nil {
+		return nil, err
+	}
+
+	all, err := dayfile.List(g.dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []dayfile.File
+	for _, f := range all {
+		// Apply date filters
+		if opts.FromDate != "" && f.Date < opts.FromDate {
+			continue
+		}
+		if opts.ToDate != "" && f.Date > opts.ToDate {
+			continue
+		}
+
+		// Reading both copies of a day would count its trades twice
+		for _, path := range f.Shadowed {
+			g.logger.Warnf("%s has more than one day file; using the newest, %s, and ignoring %s",
+				f.Date, filepath.Base(f.Path), filepath.Base(path))
+		}
+
+		files = append(files, f)
+	}
+
+	return files, nil
+}
+
+// checkDay warns when a day file's contents disagree with the date in its
+// filename, which is the date it is summarized under: the date recorded
+// inside differs, or trades started on another day (in g.loc), as after a
+// timezone change. With strict set, it returns an error instead.
+func (g *Generator) checkDay(f dayfile.File, day *models.DayExport) error {
+	var problems []string
+	if day.Date != "" && day.Date != f.Date {
+		problems = append(problems, fmt.Sprintf("holds date %s", day.Date))
+	}
+
+	if g.loc != nil {
+		moved, example := 0, ""
+		for _, t := range day.Trades {
+			start, err := time.Parse(time.RFC3339, t.StartDatetime)
+			if err != nil {
+				continue
+			}
+			if d := start.In(g.loc).Format("2006-01-02"); d != f.Date {
+				moved++
+				if example == "" {
+					example = fmt.Sprintf("trade %d on %s", t.ID, d)
+				}
+			}
+		}
+		if moved > 0 {
+			problems = append(problems, fmt.Sprintf("has %d trades that started on another day in %s (%s)", moved, g.loc, example))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("day file %s %s", filepath.Base(f.Path), strings.Join(problems, " and "))
+	if g.strict {
+		return errors.New(msg)
+	}
+	g.logger.Warnf("%s; counting it under %s", msg, f.Date)
+	return nil
+}
+
+// validateRange checks that the date filters are yyyy-mm-dd and in order.
+func validateRange(fromDate, toDate string) error {
+	if fromDate != "" {
+		if _, err := time.Parse("2006-01-02", fromDate); err != nil {
+			return fmt.Errorf("invalid --from date %q (use yyyy-mm-dd)", fromDate)
+		}
+	}
+	if toDate != "" {
+		if _, err := time.Parse("2006-01-02", toDate); err != nil {
+			return fmt.Errorf("invalid --to date %q (use yyyy-mm-dd)", toDate)
+		}
+	}
+	if fromDate != "" && toDate != "" && fromDate > toDate {
+		return fmt.Errorf("invalid date range: --from %s is after --to %s", fromDate, toDate)
+	}
+	return nil
+}
+
+// PrintTable prints summaries as a formatted ASCII table.
+func (g *Generator) PrintTable(w io.Writer, summaries []models.DailySummary, ro RenderOptions) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := strings.ToUpper(ro.dateHeader())
+	rule := strings.Repeat("─", len(header))
+
+	// --exposure columns go between VOLUME and SYMBOLS
+	var extraHeader, extraRule string
+	if ro.Exposure {
+		for _, h := range exposureHeaders {
+			extraHeader += "\t" + h
+			extraRule += "\t" + strings.Repeat("─", len(h))
+		}
+	}
+	ruleLine := fmt.Sprintf("%s\t──────\t─────────\t───────\t────\t───\t─────\t────────\t──────%s\t───────\n", rule, extraRule)
+
+	fmt.Fprintf(tw, "%s\tTRADES\tGROSS P&L\tNET P&L\tWIN%%\tSCR\tAVG R\tAVG HOLD\tVOLUME%s\tSYMBOLS\n", header, extraHeader)
+	fmt.Fprint(tw, ruleLine)
+
+	for _, s := range summaries {
+		symbols := ro.formatSymbols(s.Symbols)
+		date := ro.formatDate(s.Date)
+		if s.OpenCount > 0 {
+			date += "*"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%.0f%%\t%d\t%s\t%s\t%d%s\t%s\n",
+			date,
+			s.TradeCount,
+			ro.money(s.GrossPL),
+			ro.money(s.NetPL),
+			s.WinRate,
+			s.Scratches,
+			formatR(s.AvgRMultiple),
+			formatHold(s.AvgHoldSeconds, s.HeldTrades),
+			s.TotalVolume,
+			tabCells(ro.exposure(s)),
+			symbols,
+		)
+	}
+
+	fmt.Fprint(tw, ruleLine)
+
+	tot, periods := ro.totals(summaries)
+
+	fmt.Fprintf(tw, "TOTAL\t%d\t%s\t%s\t%.0f%%\t%d\t%s\t%s\t%d%s\t%d %s\n",
+		tot.TradeCount,
+		ro.money(tot.GrossPL),
+		ro.money(tot.NetPL),
+		tot.WinRate,
+		tot.Scratches,
+		formatR(tot.AvgRMultiple),
+		formatHold(tot.AvgHoldSeconds, tot.HeldTrades),
+		tot.TotalVolume,
+		tabCells(ro.exposure(tot)),
+		periods,
+		ro.Period.plural(),
+	)
+
+	// Separate subtotals, since P&L in different currencies can't be added up
+	if len(tot.Currencies) > 1 {
+		for _, cp := range tot.Currencies {
+			fmt.Fprintf(tw, "  %s\t%d\t%s\t\t\t\t\t\t\n", cp.Currency, cp.Count, formatNative(cp.GrossPL))
+		}
+	}
+
+	tw.Flush()
+
+	if tot.OpenCount > 0 {
+		fmt.Fprintf(w, "\n* %s\n", tot.UnrealizedNote)
+	}
+}
+
+// ExportCSV writes summaries as CSV.
+func (g *Generator) ExportCSV(w io.Writer, summaries []models.DailySummary, ro RenderOptions) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	if err := cw.Write(ro.csvHeader()); err != nil {
+		return err
+	}
+	return ro.writeCSVRows(cw, summaries)
+}
+
+// csvHeader is the header row of ExportCSV.
+func (ro RenderOptions) csvHeader() []string {
+	var header []string
+	for _, i := range ro.csvColumns() {
+		if i == 0 {
+			header = append(header, ro.dateHeader())
+			continue
+		}
+		header = append(header, csvFields[i])
+	}
+	return header
+}
+
+// writeCSVRows writes a CSV row per summary, without the header.
+func (ro RenderOptions) writeCSVRows(cw *csv.Writer, summaries []models.DailySummary) error {
+	cols := ro.csvColumns()
+	for _, s := range summaries {
+		cells := []string{
+			ro.formatDate(s.Date),
+			fmt.Sprintf("%d", s.TradeCount),
+			fmt.Sprintf("%.2f", s.GrossPL),
+			fmt.Sprintf("%.2f", s.NetPL),
+			fmt.Sprintf("%.2f", s.Commission),
+			fmt.Sprintf("%.2f", s.Fees),
+			fmt.Sprintf("%.1f", s.WinRate),
+			fmt.Sprintf("%d", s.Winners),
+			fmt.Sprintf("%d", s.Losers),
+			fmt.Sprintf("%d", s.TotalVolume),
+			formatSymbolsCSV(s.Symbols),
+			formatRCSV(s.AvgRMultiple),
+			fmt.Sprintf("%d", s.Scratches),
+			fmt.Sprintf("%d", s.OpenCount),
+			formatCurrenciesCSV(s.Currencies),
+			formatHoldCSV(s.AvgHoldSeconds, s.HeldTrades),
+			fmt.Sprintf("%d", s.IntradayCount),
+			fmt.Sprintf("%d", s.MultidayCount),
+			fmt.Sprintf("%.2f", s.TotalNotional),
+			fmt.Sprintf("%.2f", s.MaxPositionNotional),
+		}
+		row := make([]string, len(cols))
+		for j, i := range cols {
+			row[j] = cells[i]
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonReport is the document written by ExportJSON.
+type jsonReport struct {
+	GroupBy   Period                `json:"group_by"`
+	Redacted  bool                  `json:"redacted,omitempty"` // amounts are in units, see Redact
+	Summaries []models.DailySummary `json:"summaries"`
+	Total     jsonTotal             `json:"total"`
+}
+
+// jsonTotal is the totals row, with the number of periods it spans.
+type jsonTotal struct {
+	models.DailySummary
+	Periods int `json:"periods"`
+}
+
+// ExportJSON writes summaries as an indented JSON document holding the
+// per-period rows and a separate totals object.
+func (g *Generator) ExportJSON(w io.Writer, summaries []models.DailySummary, ro RenderOptions) error {
+	period := ro.Period
+	if period == "" {
+		period = PeriodDay
+	}
+
+	tot, periods := ro.totals(summaries)
+	report := jsonReport{
+		GroupBy:   period,
+		Redacted:  ro.Redacted,
+		Summaries: summaries,
+		Total: jsonTotal{
+			DailySummary: tot,
+			Periods:      periods,
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// Outcome classifies a closed trade as a win, loss, or scratch.
+type Outcome int
+
+const (
+	OutcomeScratch Outcome = iota
+	OutcomeWin
+	OutcomeLoss
+)
+
+// WinBasis is the P&L a trade is judged a winner or loser on.
+type WinBasis string
+
+const (
+	WinBasisGross WinBasis = "gross"
+	WinBasisNet   WinBasis = "net" // gross P&L minus commission and fees
+)
+
+// ParseWinBasis parses a --win-basis value. Empty means gross.
+func ParseWinBasis(s string) (WinBasis, error) {
+	switch b := WinBasis(strings.ToLower(s)); b {
+	case "":
+		return WinBasisGross, nil
+	case WinBasisGross, WinBasisNet:
+		return b, nil
+	}
+	return "", fmt.Errorf("invalid win basis %q (use gross or net)", s)
+}
+
+// PL returns the P&L of t that b judges it on.
+func (b WinBasis) PL(t models.Trade) float64 {
+	if b == WinBasisNet {
+		return tradeNetPL(t)
+	}
+	return t.GrossPL
+}
+
+// OutcomeOf classifies a trade by its P&L on basis. Trades within threshold
+// of zero are scratches. Every breakdown that counts winners and losers
+// goes through it, so --win-basis and --scratch-threshold mean the same
+// everywhere.
+func OutcomeOf(t models.Trade, threshold float64, basis WinBasis) Outcome {
+	pl := basis.PL(t)
+	switch {
+	case math.Abs(pl) <= threshold:
+		return OutcomeScratch
+	case pl > 0:
+		return OutcomeWin
+	default:
+		return OutcomeLoss
+	}
+}
+
+// FilterCurrency returns the trades made in currency (case-insensitive).
+// An empty currency returns trades unchanged.
+func FilterCurrency(trades []models.Trade, currency string) []models.Trade {
+	if currency == "" {
+		return trades
+	}
+
+	var out []models.Trade
+	for _, t := range trades {
+		if strings.EqualFold(tradeCurrency(t), currency) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// tradeCurrency is the currency a trade was made in.
+func tradeCurrency(t models.Trade) string {
+	if t.NativeCurrency != nil && *t.NativeCurrency != "" {
+		return strings.ToUpper(*t.NativeCurrency)
+	}
+	return DefaultCurrency
+}
+
+// nativePL is a trade's P&L in its own currency.
+func nativePL(t models.Trade) float64 {
+	if t.NativePL != nil {
+		return *t.NativePL
+	}
+	return t.GrossPL
+}
+
+func buildDailySummary(date string, trades []models.Trade, opts Options) models.DailySummary {
+	s := models.DailySummary{
+		Date:       date,
+		TradeCount: len(trades),
+	}
+
+	// Track symbols
+	type symAgg struct {
+		sides      map[string]bool
+		grossPL    float64
+		commission float64
+		fees       float64
+		volume     int
+		count      int
+	}
+	syms := make(map[string]*symAgg)
+	var symOrder []string
+	var rSum, holdSum, captureSum, maeSum float64
+	var openSymbols []string
+	currencies := make(map[string]*models.CurrencyPL)
+
+	for _, t := range trades {
+		agg, ok := syms[t.Symbol]
+		if !ok {
+			agg = &symAgg{sides: make(map[string]bool)}
+			syms[t.Symbol] = agg
+			symOrder = append(symOrder, t.Symbol)
+		}
+		agg.sides[t.Side] = true
+		agg.volume += t.Volume
+		agg.count++
+		s.TotalVolume += t.Volume
+
+		// A trade without an entry price has no notional to count
+		if t.EntryPrice > 0 {
+			notional := t.EntryPrice * float64(t.Volume)
+			s.TotalNotional += notional
+			s.MaxPositionNotional = math.Max(s.MaxPositionNotional, notional)
+		}
+
+		switch t.Duration {
+		case "I":
+			s.IntradayCount++
+		case "M":
+			s.MultidayCount++
+		}
+
+		// Open positions aren't realized yet, so they stay out of P&L and win rate
+		if t.Open {
+			s.OpenCount++
+			openSymbols = append(openSymbols, t.Symbol)
+			continue
+		}
+
+		s.GrossPL += t.GrossPL
+		s.Commission += t.Commission
+		s.Fees += t.Fees
+
+		if hold, ok := holdTime(t); ok {
+			holdSum += hold.Seconds()
+			s.HeldTrades++
+		}
+
+		cur := tradeCurrency(t)
+		cp, ok := currencies[cur]
+		if !ok {
+			cp = &models.CurrencyPL{Currency: cur}
+			currencies[cur] = cp
+		}
+		cp.GrossPL += nativePL(t)
+		cp.Count++
+
+		switch OutcomeOf(t, opts.ScratchThreshold, opts.WinBasis) {
+		case OutcomeWin:
+			s.Winners++
+			if t.PositionMFE != nil && *t.PositionMFE > 0 {
+				captureSum += t.GrossPL / *t.PositionMFE
+				s.CaptureTrades++
+			}
+		case OutcomeLoss:
+			s.Losers++
+		default:
+			s.Scratches++
+		}
+
+		if t.InitialRisk != nil && *t.InitialRisk != 0 {
+			rSum += t.GrossPL / *t.InitialRisk
+			s.RiskedTrades++
+		}
+		if t.PositionMAE != nil {
+			maeSum += math.Abs(*t.PositionMAE)
+			s.MAETrades++
+		}
+
+		agg.grossPL += t.GrossPL
+		agg.commission += t.Commission
+		agg.fees += t.Fees
+	}
+
+	s.NetPL = s.GrossPL - s.Commission - s.Fees
+	s.UnrealizedNote = unrealizedNote(s.OpenCount, openSymbols)
+	if s.HeldTrades > 0 {
+		s.AvgHoldSeconds = holdSum / float64(s.HeldTrades)
+	}
+	s.Currencies = sortedCurrencies(currencies)
+
+	// R-multiple is only defined for trades with a recorded initial risk
+	if s.RiskedTrades > 0 {
+		avg := rSum / float64(s.RiskedTrades)
+		s.AvgRMultiple = &avg
+	}
+	s.AvgCaptureRatio = average(captureSum, s.CaptureTrades)
+	s.AvgMAE = average(maeSum, s.MAETrades)
+
+	// Scratches are excluded from the win rate
+	if s.Winners+s.Losers > 0 {
+		s.WinRate = float64(s.Winners) / float64(s.Winners+s.Losers) * 100
+	}
+
+	for _, sym := range symOrder {
+		agg := syms[sym]
+		side := sideFromMap(agg.sides)
+		s.Symbols = append(s.Symbols, models.SymbolSummary{
+			Symbol:     sym,
+			Side:       side,
+			GrossPL:    agg.grossPL,
+			Commission: agg.commission,
+			Fees:       agg.fees,
+			NetPL:      agg.grossPL - agg.commission - agg.fees,
+			Volume:     agg.volume,
+			Count:      agg.count,
+		})
+	}
+
+	return s
+}
+
+// average returns sum / n, or nil when there is nothing to average.
+func average(sum float64, n int) *float64 {
+	if n == 0 {
+		return nil
+	}
+	avg := sum / float64(n)
+	return &avg
+}
+
+// holdTime is how long a closed trade was held. It is unknown for trades
+// missing either datetime, or whose datetimes don't parse.
+func holdTime(t models.Trade) (time.Duration, bool) {
+	if t.EndDatetime == nil {
+		return 0, false
+	}
+	start, err := time.Parse(time.RFC3339, t.StartDatetime)
+	if err != nil {
+		return 0, false
+	}
+	end, err := time.Parse(time.RFC3339, *t.EndDatetime)
+	if err != nil || end.Before(start) {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
+
+// sortedCurrencies flattens per-currency totals in currency-code order.
+func sortedCurrencies(m map[string]*models.CurrencyPL) []models.CurrencyPL {
+	out := make([]models.CurrencyPL, 0, len(m))
+	for _, cp := range m {
+		out = append(out, *cp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Currency < out[j].Currency
+	})
+	return out
+}
+
+// unrealizedNote explains that open trades were left out of realized P&L,
+// naming their symbols when known.
+func unrealizedNote(open int, symbols []string) string {
+	if open == 0 {
+		return ""
+	}
+	noun := "trades"
+	if open == 1 {
+		noun = "trade"
+	}
+	note := fmt.Sprintf("%d open %s excluded from realized P&L", open, noun)
+	if len(symbols) > 0 {
+		note += " (" + strings.Join(symbols, ", ") + ")"
+	}
+	return note
+}
+
+func sideFromMap(sides map[string]bool) string {
+	hasLong := sides["L"]
+	hasShort := sides["S"]
+	if hasLong && hasShort {
+		return "L/S"
+	}
+	if hasShort {
+		return "S"
+	}
+	if hasLong {
+		return "L"
+	}
+	// No trade recorded a side
+	return ""
+}
+
+// FormatPL renders a dollar P&L with its sign, as +$12.50 or -$3.00.
+func FormatPL(v float64) string {
+	if v >= 0 {
+		return fmt.Sprintf("+$%.2f", v)
+	}
+	return fmt.Sprintf("-$%.2f", -v)
+}
+
+// formatNative renders P&L in a trade's own currency, without a $ sign.
+func formatNative(v float64) string {
+	return fmt.Sprintf("%+.2f", v)
+}
+
+// formatCurrenciesCSV renders per-currency P&L as "USD:+10.00 EUR:-4.50".
+func formatCurrenciesCSV(cs []models.CurrencyPL) string {
+	var parts []string
+	for _, c := range cs {
+		parts = append(parts, fmt.Sprintf("%s:%+.2f", c.Currency, c.GrossPL))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatHold renders an average hold time compactly (45s, 12m30s, 3h05m,
+// 2d4h), or "n/a" when no trade had both datetimes.
+func formatHold(seconds float64, held int) string {
+	if held == 0 {
+		return "n/a"
+	}
+	d := time.Duration(seconds * float64(time.Second)).Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// formatHoldCSV renders an average hold time in seconds, leaving it empty
+// when undefined.
+func formatHoldCSV(seconds float64, held int) string {
+	if held == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f", seconds)
+}
+
+// formatR renders an average R-multiple for the table, or "n/a" when no
+// trade had a defined initial risk.
+func formatR(r *float64) string {
+	if r == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.2fR", *r)
+}
+
+// formatRCSV renders an average R-multiple for CSV, leaving undefined values empty.
+func formatRCSV(r *float64) string {
+	if r == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", *r)
+}
+
+// symbolSide renders a symbol with its side, e.g. "SNGX(L)", leaving the
+// parentheses off when the side is unknown.
+func symbolSide(symbol, side string) string {
+	if side == "" {
+		return symbol
+	}
+	return symbol + "(" + side + ")"
+}
+
+// formatSymbols renders each symbol with its net P&L, so symbols whose
+// commission and fees eat the edge stand out.
+func (ro RenderOptions) formatSymbols(syms []models.SymbolSummary) string {
+	var parts []string
+	for _, s := range syms {
+		parts = append(parts, symbolSide(s.Symbol, s.Side)+ro.money(s.NetPL))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatSymbolsCSV(syms []models.SymbolSummary) string {
+	var parts []string
+	for _, s := range syms {
+		parts = append(parts, fmt.Sprintf("%s%+.2f", symbolSide(s.Symbol, s.Side), s.NetPL))
+	}
+	return strings.Join(parts, " ")
+}
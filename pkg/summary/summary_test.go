@@ -0,0 +1,189 @@
+package summary
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+func TestValidateRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		wantErr  string // substring; "" for success
+	}{
+		{name: "no dates"},
+		{name: "from only", from: "2025-01-02"},
+		{name: "to only", to: "2025-01-31"},
+		{name: "in order", from: "2025-01-02", to: "2025-01-31"},
+		{name: "same day", from: "2025-01-02", to: "2025-01-02"},
+		{name: "inverted", from: "2025-06-01", to: "2025-01-01", wantErr: "--from 2025-06-01 is after --to 2025-01-01"},
+		{name: "malformed from", from: "2025-1-2", wantErr: `invalid --from date "2025-1-2"`},
+		{name: "impossible from", from: "2025-02-30", to: "2025-03-01", wantErr: `invalid --from date "2025-02-30"`},
+		{name: "malformed to", from: "2025-01-02", to: "Jan 31", wantErr: `invalid --to date "Jan 31"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRange(tt.from, tt.to)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateRange(%q, %q): %v", tt.from, tt.to, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateRange(%q, %q) = %v, want an error containing %q", tt.from, tt.to, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// symbolsOf lists the symbols of trades in order.
+func symbolsOf(trades []models.Trade) []string {
+	out := []string{}
+	for _, t := range trades {
+		out = append(out, t.Symbol)
+	}
+	return out
+}
+
+func TestSymbolIncludeExclude(t *testing.T) {
+	trades := []models.Trade{{ID: 1, Symbol: "AAPL"}, {ID: 2, Symbol: "TSLA"}, {ID: 3, Symbol: "spy"}, {ID: 4, Symbol: "AMD"}}
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{name: "no filters", want: []string{"AAPL", "TSLA", "spy", "AMD"}},
+		{name: "include", include: []string{"AAPL", "AMD"}, want: []string{"AAPL", "AMD"}},
+		{name: "include ignores case", include: []string{"SPY", "tsla"}, want: []string{"TSLA", "spy"}},
+		{name: "exclude", exclude: []string{"TSLA"}, want: []string{"AAPL", "spy", "AMD"}},
+		{name: "exclude ignores case", exclude: []string{"Spy"}, want: []string{"AAPL", "TSLA", "AMD"}},
+		{name: "include then exclude", include: []string{"AAPL", "TSLA", "AMD"}, exclude: []string{"AMD"}, want: []string{"AAPL", "TSLA"}},
+		{name: "exclude wins over include", include: []string{"AAPL"}, exclude: []string{"aapl"}, want: []string{}},
+		{name: "unknown symbols match nothing", include: []string{"NVDA"}, want: []string{}},
+		{name: "excluding an absent symbol", exclude: []string{"NVDA"}, want: []string{"AAPL", "TSLA", "spy", "AMD"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Symbols: tt.include, ExcludeSymbols: tt.exclude}
+			got := symbolsOf(opts.filter(slices.Clone(trades)))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("include %v, exclude %v: got %v, want %v", tt.include, tt.exclude, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmptySymbolIsUnknown(t *testing.T) {
+	trades := []models.Trade{
+		{ID: 1, Symbol: "", Side: "L", GrossPL: 10},
+		{ID: 2, Symbol: "  ", Side: "L", GrossPL: -4},
+		{ID: 3, Symbol: " AAPL ", Side: "S", GrossPL: 5},
+	}
+
+	got := Options{}.filter(trades)
+	if want := []string{models.UnknownSymbol, models.UnknownSymbol, "AAPL"}; !slices.Equal(symbolsOf(got), want) {
+		t.Errorf("filtered symbols = %v, want %v", symbolsOf(got), want)
+	}
+	// The caller's trades are left as exported
+	if want := []string{"", "  ", " AAPL "}; !slices.Equal(symbolsOf(trades), want) {
+		t.Errorf("filter changed the caller's symbols to %v, want %v", symbolsOf(trades), want)
+	}
+
+	if got := (Options{Symbols: []string{"unknown"}}).filter(trades); len(got) != 2 {
+		t.Errorf("--symbol UNKNOWN kept %d trades, want 2", len(got))
+	}
+
+	s := buildDailySummary("2025-01-02", Options{}.filter(trades), Options{})
+	var unknown *models.SymbolSummary
+	for i := range s.Symbols {
+		if s.Symbols[i].Symbol == "" {
+			t.Errorf("summary has a blank symbol row: %+v", s.Symbols[i])
+		}
+		if s.Symbols[i].Symbol == models.UnknownSymbol {
+			unknown = &s.Symbols[i]
+		}
+	}
+	if unknown == nil {
+		t.Fatalf("summary symbols %+v have no %s row", s.Symbols, models.UnknownSymbol)
+	}
+	if unknown.Count != 2 || unknown.GrossPL != 6 {
+		t.Errorf("%s row = %d trades, $%.2f; want 2 trades, $6.00", models.UnknownSymbol, unknown.Count, unknown.GrossPL)
+	}
+}
+
+func TestWinBasis(t *testing.T) {
+	trades := []models.Trade{
+		{ID: 1, Symbol: "AAPL", GrossPL: 10, Commission: 3, Fees: 2},  // net +5
+		{ID: 2, Symbol: "AAPL", GrossPL: 4, Commission: 3, Fees: 2},   // net -1: fees turn it into a loss
+		{ID: 3, Symbol: "TSLA", GrossPL: 2, Commission: 1, Fees: 1},   // net 0
+		{ID: 4, Symbol: "TSLA", GrossPL: -5, Commission: 1},           // net -6
+		{ID: 5, Symbol: "TSLA", GrossPL: 0, Commission: 1, Fees: 0.5}, // net -1.5
+	}
+
+	tests := []struct {
+		name                       string
+		basis                      WinBasis
+		threshold                  float64
+		winners, losers, scratches int
+	}{
+		{name: "gross", basis: WinBasisGross, winners: 3, losers: 1, scratches: 1},
+		{name: "default is gross", winners: 3, losers: 1, scratches: 1},
+		{name: "net", basis: WinBasisNet, winners: 1, losers: 3, scratches: 1},
+		{name: "gross with threshold", basis: WinBasisGross, threshold: 2, winners: 2, losers: 1, scratches: 2},
+		{name: "net threshold applies to net P&L", basis: WinBasisNet, threshold: 1.5, winners: 1, losers: 1, scratches: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{WinBasis: tt.basis, ScratchThreshold: tt.threshold}
+			s := buildDailySummary("2025-01-02", opts.filter(trades), opts)
+			if s.Winners != tt.winners || s.Losers != tt.losers || s.Scratches != tt.scratches {
+				t.Errorf("winners/losers/scratches = %d/%d/%d, want %d/%d/%d",
+					s.Winners, s.Losers, s.Scratches, tt.winners, tt.losers, tt.scratches)
+			}
+			// The basis only decides outcomes, never the P&L totals
+			if s.GrossPL != 11 || s.NetPL != -3.5 {
+				t.Errorf("gross/net P&L = %.2f/%.2f, want 11.00/-3.50", s.GrossPL, s.NetPL)
+			}
+		})
+	}
+}
+
+func TestParseWinBasis(t *testing.T) {
+	for in, want := range map[string]WinBasis{"": WinBasisGross, "gross": WinBasisGross, "NET": WinBasisNet} {
+		if got, err := ParseWinBasis(in); err != nil || got != want {
+			t.Errorf("ParseWinBasis(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseWinBasis("realized"); err == nil {
+		t.Error("ParseWinBasis(\"realized\") succeeded, want an error")
+	}
+}
+
+func TestCSVKeepsOriginalColumns(t *testing.T) {
+	// The header before any columns were added; sheets may read it by position
+	original := []string{"date", "trades", "gross_pl", "net_pl", "commission", "fees",
+		"win_rate", "winners", "losers", "volume", "symbols"}
+
+	var buf strings.Builder
+	g := NewGenerator(t.TempDir())
+	day := models.DailySummary{Date: "2025-01-02", TradeCount: 2, Winners: 1, Scratches: 1, TotalVolume: 300,
+		Symbols: []models.SymbolSummary{{Symbol: "AAPL", Side: "L", NetPL: 12.5}}}
+	if err := g.ExportCSV(&buf, []models.DailySummary{day}, RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	header, row := strings.Split(lines[0], ","), strings.Split(lines[1], ",")
+	if len(header) < len(original) || !slices.Equal(header[:len(original)], original) {
+		t.Fatalf("header = %v, want it to start with %v", header, original)
+	}
+	if row[9] != "300" || row[10] != "AAPL(L)+12.50" {
+		t.Errorf("volume and symbols cells = %q, %q, want %q, %q", row[9], row[10], "300", "AAPL(L)+12.50")
+	}
+}
@@ -0,0 +1,174 @@
+package summary
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// Untagged labels the bucket of trades that carry no tags.
+const Untagged = "(untagged)"
+
+// TagSummary is the realized performance of all trades carrying one tag.
+type TagSummary struct {
+	Tag        string  `json:"tag"`
+	TradeCount int     `json:"trade_count"`
+	GrossPL    float64 `json:"gross_pl"`
+	NetPL      float64 `json:"net_pl"`
+	Winners    int     `json:"winners"`
+	Losers     int     `json:"losers"`
+	Scratches  int     `json:"scratches"`
+	WinRate    float64 `json:"win_rate"`
+	OpenCount  int     `json:"open_count"`
+}
+
+// TagSummaries breaks the trades matching opts down by tag, sorted by net
+// P&L (best first). A trade with several tags counts toward each of them,
+// so the per-tag totals can add up to more than the account total.
+func (g *Generator) TagSummaries(opts Options) ([]TagSummary, error) {
+	return g.breakdown(opts, func(t models.Trade) []string { return uniqueTags(t.Tags) }, Untagged)
+}
+
+// FlagSummaries is TagSummaries by the local flags in opts.Annotations
+// instead of Tradervue tags.
+func (g *Generator) FlagSummaries(opts Options) ([]TagSummary, error) {
+	return g.breakdown(opts, func(t models.Trade) []string { return opts.Annotations.Flags(t.ID) }, Unflagged)
+}
+
+// breakdown totals the trades matching opts under each of the labels
+// labelsOf gives them, or under none when it gives none.
+func (g *Generator) breakdown(opts Options, labelsOf func(models.Trade) []string, none string) ([]TagSummary, error) {
+	days, err := g.Days(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	byTag := make(map[string]*TagSummary)
+	for _, day := range days {
+		for _, t := range opts.filter(day.Trades) {
+			tags := labelsOf(t)
+			if len(tags) == 0 {
+				tags = []string{none}
+			}
+			for _, tag := range tags {
+				ts, ok := byTag[tag]
+				if !ok {
+					ts = &TagSummary{Tag: tag}
+					byTag[tag] = ts
+				}
+				addToTag(ts, t, opts)
+			}
+		}
+	}
+
+	out := make([]TagSummary, 0, len(byTag))
+	for _, ts := range byTag {
+		if ts.Winners+ts.Losers > 0 {
+			ts.WinRate = float64(ts.Winners) / float64(ts.Winners+ts.Losers) * 100
+		}
+		out = append(out, *ts)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].NetPL != out[j].NetPL {
+			return out[i].NetPL > out[j].NetPL
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	return out, nil
+}
+
+// addToTag folds one trade into a tag's totals. Open trades are counted but
+// stay out of P&L and win rate, as in the daily summaries.
+func addToTag(ts *TagSummary, t models.Trade, opts Options) {
+	ts.TradeCount++
+	if t.Open {
+		ts.OpenCount++
+		return
+	}
+
+	ts.GrossPL += t.GrossPL
+	ts.NetPL += tradeNetPL(t)
+	switch OutcomeOf(t, opts.ScratchThreshold, opts.WinBasis) {
+	case OutcomeWin:
+		ts.Winners++
+	case OutcomeLoss:
+		ts.Losers++
+	default:
+		ts.Scratches++
+	}
+}
+
+// uniqueTags lowercases tags and drops duplicates, so "News" and "news"
+// on the same trade count once.
+func uniqueTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var out []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+// PrintTags prints per-tag summaries as a formatted ASCII table.
+func (g *Generator) PrintTags(w io.Writer, tags []TagSummary) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "TAG\tTRADES\tGROSS P&L\tNET P&L\tWIN%%\tW/L/S\n")
+	fmt.Fprintf(tw, "───\t──────\t─────────\t───────\t────\t─────\n")
+
+	for _, t := range tags {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%.0f%%\t%d/%d/%d\n",
+			t.Tag,
+			t.TradeCount,
+			FormatPL(t.GrossPL),
+			FormatPL(t.NetPL),
+			t.WinRate,
+			t.Winners,
+			t.Losers,
+			t.Scratches,
+		)
+	}
+
+	tw.Flush()
+}
+
+// ExportTagsCSV writes per-tag summaries as CSV.
+func (g *Generator) ExportTagsCSV(w io.Writer, tags []TagSummary) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	// Header
+	if err := cw.Write([]string{
+		"tag", "trades", "gross_pl", "net_pl", "win_rate", "winners", "losers", "scratches", "open",
+	}); err != nil {
+		return err
+	}
+
+	for _, t := range tags {
+		if err := cw.Write([]string{
+			t.Tag,
+			fmt.Sprintf("%d", t.TradeCount),
+			fmt.Sprintf("%.2f", t.GrossPL),
+			fmt.Sprintf("%.2f", t.NetPL),
+			fmt.Sprintf("%.1f", t.WinRate),
+			fmt.Sprintf("%d", t.Winners),
+			fmt.Sprintf("%d", t.Losers),
+			fmt.Sprintf("%d", t.Scratches),
+			fmt.Sprintf("%d", t.OpenCount),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package summary
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+)
+
+// tagDelimiter joins a trade's tags into a single CSV cell.
+const tagDelimiter = ";"
+
+// DayTrade is a trade together with the trading day it was exported under.
+type DayTrade struct {
+	Date       string
+	Trade      models.Trade
+	Executions []models.Execution // empty unless exported with --with-executions
+}
+
+// Trades flattens the day files in range into one trade per row, applying
+// the same filters as Generate. Rows are ordered by day, then as exported.
+func (g *Generator) Trades(opts Options) ([]DayTrade, error) {
+	days, err := g.Days(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []DayTrade
+	for _, day := range days {
+		for _, t := range opts.filter(day.Trades) {
+			rows = append(rows, DayTrade{Date: day.Date, Trade: t, Executions: day.Executions[t.ID]})
+		}
+	}
+	return rows, nil
+}
+
+// Recent orders rows newest first by start time and returns at most limit
+// of them (all when limit is 0). When after is non-zero, the listing starts
+// just past the trade with that ID, so the last ID of one page fetches the
+// next.
+func Recent(rows []DayTrade, after, limit int) ([]DayTrade, error) {
+	sorted := make([]DayTrade, len(rows))
+	copy(sorted, rows)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return tradeStart(sorted[i]).After(tradeStart(sorted[j]))
+	})
+
+	if after != 0 {
+		idx := -1
+		for i, r := range sorted {
+			if r.Trade.ID == after {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("trade %d not found in the selected trades", after)
+		}
+		sorted = sorted[idx+1:]
+	}
+
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted, nil
+}
+
+// tradeStart is a trade's start time, falling back to its day when the
+// start datetime doesn't parse.
+func tradeStart(r DayTrade) time.Time {
+	if t, err := time.Parse(time.RFC3339, r.Trade.StartDatetime); err == nil {
+		return t
+	}
+	t, _ := time.Parse("2006-01-02", r.Date)
+	return t
+}
+
+// ExportTradesCSV writes one CSV row per trade.
+func (g *Generator) ExportTradesCSV(w io.Writer, rows []DayTrade) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	// Header
+	if err := cw.Write([]string{
+		"date", "id", "symbol", "side", "volume", "entry_price", "exit_price",
+		"gross_pl", "commission", "fees", "net_pl", "duration", "open",
+		"start_datetime", "end_datetime", "tags",
+	}); err != nil {
+		return err
+	}
+
+	for _, r := range rows {
+		t := r.Trade
+		if err := cw.Write([]string{
+			r.Date,
+			fmt.Sprintf("%d", t.ID),
+			t.Symbol,
+			t.Side,
+			fmt.Sprintf("%d", t.Volume),
+			fmt.Sprintf("%.4f", t.EntryPrice),
+			formatOptionalPrice(t.ExitPrice),
+			fmt.Sprintf("%.2f", t.GrossPL),
+			fmt.Sprintf("%.2f", t.Commission),
+			fmt.Sprintf("%.2f", t.Fees),
+			fmt.Sprintf("%.2f", tradeNetPL(t)),
+			t.Duration,
+			fmt.Sprintf("%t", t.Open),
+			t.StartDatetime,
+			derefString(t.EndDatetime),
+			strings.Join(t.Tags, tagDelimiter),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExportTradesJSONL writes one compact Trade JSON object per line.
+func (g *Generator) ExportTradesJSONL(w io.Writer, rows []DayTrade) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, r := range rows {
+		if err := enc.Encode(r.Trade); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// executionLine is an execution tagged with the trade and day it belongs to,
+// since Execution itself carries no trade ID.
+type executionLine struct {
+	Date    string `json:"date"`
+	TradeID int    `json:"trade_id"`
+	models.Execution
+}
+
+// ExportExecutionsJSONL writes one execution JSON object per line.
+func (g *Generator) ExportExecutionsJSONL(w io.Writer, rows []DayTrade) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, r := range rows {
+		for _, ex := range r.Executions {
+			if err := enc.Encode(executionLine{Date: r.Date, TradeID: r.Trade.ID, Execution: ex}); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// PrintTrades prints trades as a formatted ASCII table.
+func (g *Generator) PrintTrades(w io.Writer, rows []DayTrade) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "DATE\tID\tSYMBOL\tSIDE\tVOLUME\tENTRY\tEXIT\tGROSS P&L\tNET P&L\tTAGS\n")
+	fmt.Fprintf(tw, "────\t──\t──────\t────\t──────\t─────\t────\t─────────\t───────\t────\n")
+
+	for _, r := range rows {
+		t := r.Trade
+		exit := formatOptionalPrice(t.ExitPrice)
+		if exit == "" {
+			exit = "open"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%d\t%.4f\t%s\t%s\t%s\t%s\n",
+			r.Date,
+			t.ID,
+			t.Symbol,
+			t.Side,
+			t.Volume,
+			t.EntryPrice,
+			exit,
+			FormatPL(t.GrossPL),
+			FormatPL(tradeNetPL(t)),
+			strings.Join(t.Tags, ", "),
+		)
+	}
+
+	tw.Flush()
+}
+
+// tradeNetPL is a trade's gross P&L after commission and fees.
+func tradeNetPL(t models.Trade) float64 {
+	return t.GrossPL - t.Commission - t.Fees
+}
+
+func formatOptionalPrice(p *float64) string {
+	if p == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.4f", *p)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,143 @@
+package summary
+
+import (
+	"io"
+	"strings"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+
+	"github.com/xuri/excelize/v2" // pure Go, like the SQLite driver
+)
+
+// xlsxMoneyFmt shows amounts as dollars, negatives in red.
+const xlsxMoneyFmt = `"$"#,##0.00;[Red]-"$"#,##0.00`
+
+// xlsxPercentFmt is the index of Excel's built-in 0.00% number format.
+const xlsxPercentFmt = 10
+
+// ExportXLSX writes summaries as an Excel workbook: a sheet with one row per
+// period and a totals row, and a sheet breaking each period down by symbol.
+// P&L columns are formatted as currency and the win rate as a percentage.
+func (g *Generator) ExportXLSX(w io.Writer, summaries []models.DailySummary, ro RenderOptions) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	styles, err := newXLSXStyles(f)
+	if err != nil {
+		return err
+	}
+
+	const summarySheet, symbolSheet = "Summary", "Symbols"
+	if err := f.SetSheetName("Sheet1", summarySheet); err != nil {
+		return err
+	}
+	if _, err := f.NewSheet(symbolSheet); err != nil {
+		return err
+	}
+
+	dateHeader := strings.ToUpper(ro.dateHeader()[:1]) + ro.dateHeader()[1:]
+
+	// Summary sheet: C-F are money, G is the win rate
+	rows := [][]any{{dateHeader, "Trades", "Gross P&L", "Net P&L", "Commission", "Fees",
+		"Win Rate", "Winners", "Losers", "Scratches", "Open", "Volume"}}
+	for _, s := range summaries {
+		rows = append(rows, xlsxSummaryRow(ro.formatDate(s.Date), s))
+	}
+	tot, _ := ro.totals(summaries)
+	rows = append(rows, xlsxSummaryRow("Total", tot))
+
+	if err := writeXLSXRows(f, summarySheet, rows); err != nil {
+		return err
+	}
+	last := len(rows)
+	if err := styles.apply(f, summarySheet, last, "C", "F", "G", "L"); err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(summarySheet, cell("A", last), cell("A", last), styles.header); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(summarySheet, "A", "L", 13); err != nil {
+		return err
+	}
+
+	// Symbols sheet: E-H are money
+	rows = [][]any{{dateHeader, "Symbol", "Side", "Trades", "Gross P&L", "Commission", "Fees", "Net P&L", "Volume"}}
+	for _, s := range summaries {
+		for _, sym := range s.Symbols {
+			rows = append(rows, []any{ro.formatDate(s.Date), sym.Symbol, sym.Side, sym.Count,
+				sym.GrossPL, sym.Commission, sym.Fees, sym.NetPL, sym.Volume})
+		}
+	}
+	if err := writeXLSXRows(f, symbolSheet, rows); err != nil {
+		return err
+	}
+	if err := styles.apply(f, symbolSheet, len(rows), "E", "H", "", "I"); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(symbolSheet, "A", "I", 13); err != nil {
+		return err
+	}
+
+	return f.Write(w)
+}
+
+// xlsxSummaryRow is one Summary sheet row. The win rate is stored as a
+// fraction so Excel's percentage format displays it.
+func xlsxSummaryRow(label string, s models.DailySummary) []any {
+	return []any{label, s.TradeCount, s.GrossPL, s.NetPL, s.Commission, s.Fees,
+		s.WinRate / 100, s.Winners, s.Losers, s.Scratches, s.OpenCount, s.TotalVolume}
+}
+
+// xlsxStyles are the cell styles shared by the sheets.
+type xlsxStyles struct {
+	header, money, percent int
+}
+
+func newXLSXStyles(f *excelize.File) (xlsxStyles, error) {
+	var s xlsxStyles
+	var err error
+	moneyFmt := xlsxMoneyFmt
+	if s.header, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}}); err != nil {
+		return s, err
+	}
+	if s.money, err = f.NewStyle(&excelize.Style{CustomNumFmt: &moneyFmt}); err != nil {
+		return s, err
+	}
+	if s.percent, err = f.NewStyle(&excelize.Style{NumFmt: xlsxPercentFmt}); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// apply styles the header row, the money columns from moneyFrom to moneyTo,
+// and the percent column if any, over rows 2 to last.
+func (s xlsxStyles) apply(f *excelize.File, sheet string, last int, moneyFrom, moneyTo, percentCol, lastCol string) error {
+	if err := f.SetCellStyle(sheet, "A1", cell(lastCol, 1), s.header); err != nil {
+		return err
+	}
+	if last < 2 {
+		return nil
+	}
+	if err := f.SetCellStyle(sheet, cell(moneyFrom, 2), cell(moneyTo, last), s.money); err != nil {
+		return err
+	}
+	if percentCol != "" {
+		return f.SetCellStyle(sheet, cell(percentCol, 2), cell(percentCol, last), s.percent)
+	}
+	return nil
+}
+
+func writeXLSXRows(f *excelize.File, sheet string, rows [][]any) error {
+	for i, row := range rows {
+		if err := f.SetSheetRow(sheet, cell("A", i+1), &row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cell names a cell, e.g. cell("C", 2) is "C2".
+func cell(col string, row int) string {
+	name, _ := excelize.JoinCellName(col, row)
+	return name
+}
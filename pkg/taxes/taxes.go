@@ -0,0 +1,295 @@
+// Package taxes turns closed trades into realized gain rows in the shape of
+// IRS Form 8949: what was sold, when it was acquired and sold, proceeds,
+// cost basis, and the gain or loss.
+package taxes
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jefrnc/tradervue-utils/pkg/models"
+	"github.com/jefrnc/tradervue-utils/pkg/summary"
+)
+
+// washSaleWindow is how many days either side of a loss a repurchase of the
+// same symbol makes it a possible wash sale.
+const washSaleWindow = 30
+
+// Term classifies a gain by holding period.
+type Term string
+
+const (
+	ShortTerm Term = "short" // held one year or less, and every short sale
+	LongTerm  Term = "long"  // held more than one year
+)
+
+// Lot is the realized gain or loss of one closed trade.
+type Lot struct {
+	TradeID        int     `json:"trade_id"`
+	Symbol         string  `json:"symbol"`
+	Side           string  `json:"side"` // "L" or "S"
+	Quantity       int     `json:"quantity"`
+	DateAcquired   string  `json:"date_acquired"` // yyyy-mm-dd the position was opened
+	DateSold       string  `json:"date_sold"`     // yyyy-mm-dd it was closed
+	Proceeds       float64 `json:"proceeds"`      // after selling commission and fees
+	CostBasis      float64 `json:"cost_basis"`    // including buying commission and fees
+	GainLoss       float64 `json:"gain_loss"`
+	Term           Term    `json:"term"`
+	FromExecutions bool    `json:"from_executions"` // amounts summed from executions rather than derived from the trade
+
+	// PossibleWashSale marks a loss with another trade in the same symbol
+	// and direction opened within 30 days of it; the loss may be disallowed.
+	PossibleWashSale bool `json:"possible_wash_sale"`
+}
+
+// Lots returns a lot for every closed trade in rows sold during year,
+// ordered by sale date. rows should hold all trades, not just that year's,
+// so positions opened earlier and repurchases around the year's edges are
+// seen.
+func Lots(rows []summary.DayTrade, year int) []Lot {
+	var lots []Lot
+	for _, r := range rows {
+		lot, ok := newLot(r)
+		if !ok || !inYear(lot.DateSold, year) {
+			continue
+		}
+		lots = append(lots, lot)
+	}
+
+	flagWashSales(lots, rows)
+
+	sort.SliceStable(lots, func(i, j int) bool {
+		if lots[i].DateSold != lots[j].DateSold {
+			return lots[i].DateSold < lots[j].DateSold
+		}
+		return lots[i].TradeID < lots[j].TradeID
+	})
+	return lots
+}
+
+// newLot computes a trade's lot. Open trades, and trades without an end
+// time, have none.
+func newLot(r summary.DayTrade) (Lot, bool) {
+	t := r.Trade
+	if t.Open || t.EndDatetime == nil {
+		return Lot{}, false
+	}
+	acquired := tradeDate(t.StartDatetime, r.Date)
+	sold := tradeDate(*t.EndDatetime, "")
+	if sold == "" {
+		return Lot{}, false
+	}
+
+	lot := Lot{
+		TradeID:      t.ID,
+		Symbol:       t.Symbol,
+		Side:         t.Side,
+		Quantity:     t.Volume,
+		DateAcquired: acquired,
+		DateSold:     sold,
+		Term:         term(t.Side, acquired, sold),
+	}
+
+	if basis, proceeds, ok := fromExecutions(t, r.Executions); ok {
+		lot.CostBasis, lot.Proceeds, lot.FromExecutions = basis, proceeds, true
+	} else {
+		// Entry price times volume is what was paid (long) or received
+		// (short); the gross P&L gives the other side
+		opened := t.EntryPrice * float64(t.Volume)
+		if t.Side == "S" {
+			lot.Proceeds, lot.CostBasis = opened, opened-t.GrossPL
+		} else {
+			lot.CostBasis, lot.Proceeds = opened, opened+t.GrossPL
+		}
+		lot.Proceeds -= t.Commission + t.Fees
+	}
+
+	lot.Proceeds = roundCents(lot.Proceeds)
+	lot.CostBasis = roundCents(lot.CostBasis)
+	lot.GainLoss = roundCents(lot.Proceeds - lot.CostBasis)
+	return lot, true
+}
+
+// fromExecutions sums a trade's fills into a cost basis (buys plus their
+// fees) and proceeds (sells less their fees), as Form 8949 expects. It only
+// trusts the fills when they add up to the trade's gross P&L, which they
+// don't for, say, options with a multiplier.
+func fromExecutions(t models.Trade, execs []models.Execution) (basis, proceeds float64, ok bool) {
+	if len(execs) == 0 {
+		return 0, 0, false
+	}
+	var bought, sold, buyFees, sellFees float64
+	for _, x := range execs {
+		amount := math.Abs(float64(x.Quantity)) * x.Price
+		fee := x.Commission + x.TransFee + x.ECNFee
+		if x.Quantity > 0 {
+			bought += amount
+			buyFees += fee
+		} else {
+			sold += amount
+			sellFees += fee
+		}
+	}
+	if math.Abs((sold-bought)-t.GrossPL) > 0.01 {
+		return 0, 0, false
+	}
+	return bought + buyFees, sold - sellFees, true
+}
+
+// term is the holding period classification. A short sale's gain is short
+// term however long it was held.
+func term(side, acquired, sold string) Term {
+	if side == "S" {
+		return ShortTerm
+	}
+	a, err1 := time.Parse("2006-01-02", acquired)
+	s, err2 := time.Parse("2006-01-02", sold)
+	if err1 != nil || err2 != nil {
+		return ShortTerm
+	}
+	// More than one year: sold after the first anniversary
+	if s.After(a.AddDate(1, 0, 0)) {
+		return LongTerm
+	}
+	return ShortTerm
+}
+
+// flagWashSales marks losses with a same-symbol, same-side trade opened
+// within washSaleWindow days of the sale.
+func flagWashSales(lots []Lot, rows []summary.DayTrade) {
+	opened := make(map[string][]summary.DayTrade) // symbol+side -> trades
+	for _, r := range rows {
+		key := r.Trade.Symbol + "/" + r.Trade.Side
+		opened[key] = append(opened[key], r)
+	}
+
+	for i := range lots {
+		lot := &lots[i]
+		if lot.GainLoss >= 0 {
+			continue
+		}
+		sold, err := time.Parse("2006-01-02", lot.DateSold)
+		if err != nil {
+			continue
+		}
+		for _, r := range opened[lot.Symbol+"/"+lot.Side] {
+			if r.Trade.ID == lot.TradeID {
+				continue
+			}
+			d, err := time.Parse("2006-01-02", tradeDate(r.Trade.StartDatetime, r.Date))
+			if err != nil {
+				continue
+			}
+			days := d.Sub(sold).Hours() / 24
+			if math.Abs(days) <= washSaleWindow {
+				lot.PossibleWashSale = true
+				break
+			}
+		}
+	}
+}
+
+// tradeDate is the yyyy-mm-dd part of a Tradervue datetime, in the offset
+// it was recorded with, or fallback when it doesn't parse.
+func tradeDate(datetime, fallback string) string {
+	t, err := time.Parse(time.RFC3339, datetime)
+	if err != nil {
+		return fallback
+	}
+	return t.Format("2006-01-02")
+}
+
+func inYear(date string, year int) bool {
+	return len(date) >= 4 && date[:4] == fmt.Sprintf("%04d", year)
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Totals is the sum of a set of lots by term.
+type Totals struct {
+	ShortTerm float64
+	LongTerm  float64
+	WashSales int // lots flagged as possible wash sales
+}
+
+// Sum totals the gains and losses of lots.
+func Sum(lots []Lot) Totals {
+	var t Totals
+	for _, l := range lots {
+		if l.Term == LongTerm {
+			t.LongTerm += l.GainLoss
+		} else {
+			t.ShortTerm += l.GainLoss
+		}
+		if l.PossibleWashSale {
+			t.WashSales++
+		}
+	}
+	return t
+}
+
+// ExportCSV writes one row per lot, in Form 8949 column order.
+func ExportCSV(w io.Writer, lots []Lot) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	if err := cw.Write([]string{
+		"description", "date_acquired", "date_sold", "proceeds", "cost_basis",
+		"gain_loss", "term", "possible_wash_sale", "trade_id", "symbol", "side", "quantity",
+	}); err != nil {
+		return err
+	}
+
+	for _, l := range lots {
+		if err := cw.Write([]string{
+			fmt.Sprintf("%d sh %s", l.Quantity, l.Symbol),
+			l.DateAcquired,
+			l.DateSold,
+			fmt.Sprintf("%.2f", l.Proceeds),
+			fmt.Sprintf("%.2f", l.CostBasis),
+			fmt.Sprintf("%.2f", l.GainLoss),
+			string(l.Term),
+			fmt.Sprintf("%t", l.PossibleWashSale),
+			fmt.Sprintf("%d", l.TradeID),
+			l.Symbol,
+			l.Side,
+			fmt.Sprintf("%d", l.Quantity),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintLots prints lots as a table with short- and long-term totals.
+func PrintLots(w io.Writer, lots []Lot) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "SOLD\tACQUIRED\tSYMBOL\tSIDE\tQTY\tPROCEEDS\tCOST BASIS\tGAIN/LOSS\tTERM\t\n")
+	fmt.Fprintf(tw, "────\t────────\t──────\t────\t───\t────────\t──────────\t─────────\t────\t\n")
+	for _, l := range lots {
+		wash := ""
+		if l.PossibleWashSale {
+			wash = "W?"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t$%.2f\t$%.2f\t%s\t%s\t%s\n",
+			l.DateSold, l.DateAcquired, l.Symbol, l.Side, l.Quantity,
+			l.Proceeds, l.CostBasis, summary.FormatPL(l.GainLoss), l.Term, wash)
+	}
+	tw.Flush()
+
+	t := Sum(lots)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Short-term: %s\n", summary.FormatPL(t.ShortTerm))
+	fmt.Fprintf(w, "Long-term:  %s\n", summary.FormatPL(t.LongTerm))
+	if t.WashSales > 0 {
+		fmt.Fprintf(w, "\nW? %d losses have a same-symbol trade opened within %d days and may be wash sales.\n", t.WashSales, washSaleWindow)
+	}
+}